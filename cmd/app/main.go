@@ -1,6 +1,11 @@
 package main
 
-import "github.com/AnatoliyBr/todo-app/internal/app"
+import (
+	// time zone database for the scratch image
+	_ "time/tzdata"
+
+	"github.com/AnatoliyBr/todo-app/internal/app"
+)
 
 func main() {
 	app.Run()
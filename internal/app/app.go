@@ -1,17 +1,34 @@
 package app
 
 import (
+	"context"
 	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/AnatoliyBr/todo-app/internal/controller/apiserver"
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/health"
+	"github.com/AnatoliyBr/todo-app/internal/notify"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+	"github.com/AnatoliyBr/todo-app/internal/oidc"
+	"github.com/AnatoliyBr/todo-app/internal/ratelimit"
+	"github.com/AnatoliyBr/todo-app/internal/scheduler"
+	"github.com/AnatoliyBr/todo-app/internal/signing"
 	"github.com/AnatoliyBr/todo-app/internal/store"
 	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
 	"github.com/AnatoliyBr/todo-app/internal/usecase"
 	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 )
 
+// migrationsDir is where migrate reads the migrations from.
+const migrationsDir = "migrations"
+
 var configPath string
 
 func init() {
@@ -29,28 +46,166 @@ func Run() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer db.Close()
-
-	// Repository
-	ur := sqlrepository.NewUserRepository(db)
-	lr := sqlrepository.NewListRepository(db)
 
 	// Store
-	store := store.NewAppStore(ur, lr)
+	store := sqlrepository.NewStore(db)
 
 	// UseCase
-	uc := usecase.NewAppUseCase(store)
+	flag.Parse()
+	configUseCase := usecase.NewConfig()
+	_, err = toml.DecodeFile(configPath, configUseCase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hasher, err := usecase.NewPasswordHasher(configUseCase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := entity.SetPasswordHasher(hasher); err != nil {
+		log.Fatal(err)
+	}
+
+	mfaKey, err := usecase.NewMFAKey(configUseCase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if configUseCase.MFAKey == "" {
+		logrus.Warn("no mfa key configured, totp secrets are encrypted with a generated key")
+	}
+
+	// Email
+	configEmail := email.NewConfig()
+	_, err = toml.DecodeFile(configPath, configEmail)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sender, err := email.NewSender(configEmail)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mailer, err := email.NewMailer(sender)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Notifier
+	configNotify := notify.NewConfig()
+	_, err = toml.DecodeFile(configPath, configNotify)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notifier, err := notify.NewNotifier(configNotify, mailer, logrus.StandardLogger())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	uc := usecase.NewAppUseCase(configUseCase, store).WithNotifier(notifier).WithMailer(mailer).WithMFAKey(mfaKey)
+
+	// Scheduler
+	configScheduler := scheduler.NewConfig()
+	_, err = toml.DecodeFile(configPath, configScheduler)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sched, err := scheduler.NewScheduler(configScheduler, uc, logrus.StandardLogger())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		sched.Run(ctx)
+	}()
+
+	// Health
+	migration, err := health.LatestMigration(migrationsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	checks := health.NewChecker()
+	checks.Register("db", db.PingContext)
+	checks.Register("migrations", health.Migrations(db, migration))
+	checks.Register("scheduler", sched.Check)
 
 	// Controller
-	flag.Parse()
 	configServer := apiserver.NewConfig()
 	_, err = toml.DecodeFile(configPath, configServer)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	s := apiserver.NewServer(configServer, uc)
-	if err := s.StartServer(); err != nil {
+	proxies, err := apiserver.NewTrustedProxies(configServer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	configOIDC := oidc.NewConfig()
+	_, err = toml.DecodeFile(configPath, configOIDC)
+	if err != nil {
 		log.Fatal(err)
 	}
+
+	var provider *oidc.Provider
+	if configOIDC.Enabled() {
+		provider = oidc.NewProvider(configOIDC)
+	}
+
+	configSigning := signing.NewConfig()
+	_, err = toml.DecodeFile(configPath, configSigning)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keys, err := signing.Load(configSigning)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(configSigning.KeyFiles) == 0 {
+		logrus.Warn("no signing keys configured, tokens are signed with a generated key")
+	}
+
+	configRateLimit := ratelimit.NewConfig()
+	_, err = toml.DecodeFile(configPath, configRateLimit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	limiter, err := ratelimit.NewLimiter(configRateLimit, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := apiserver.NewServer(configServer, uc, keys).
+		WithOIDC(provider).
+		WithRateLimiter(limiter).
+		WithTrustedProxies(proxies).
+		WithHealth(checks)
+	serverErr := s.StartServer(ctx)
+
+	// Shutdown
+	stop()
+	workers.Wait()
+	logrus.Info("background workers stopped")
+
+	if err := db.Close(); err != nil {
+		logrus.Errorf("closing db: %v", err)
+	}
+
+	if serverErr != nil {
+		log.Fatal(serverErr)
+	}
 }
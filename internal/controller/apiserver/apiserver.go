@@ -4,12 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/health"
+	"github.com/AnatoliyBr/todo-app/internal/oidc"
+	"github.com/AnatoliyBr/todo-app/internal/ratelimit"
+	"github.com/AnatoliyBr/todo-app/internal/signing"
 	"github.com/AnatoliyBr/todo-app/internal/usecase"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -21,29 +28,73 @@ import (
 const (
 	ctxKeyUser ctxKey = iota
 	ctxKeyRequestID
+	ctxKeyAPIKey
 )
 
 var (
-	errIncorrectEmailOrPassword = errors.New("incorrect email or password")
-	errIncorrectAuthHeader      = errors.New("incorrect auth header")
-	errNotAuthenticated         = errors.New("not authenticated")
+	errIncorrectAuthHeader  = errors.New("incorrect auth header")
+	errNotAuthenticated     = errors.New("not authenticated")
+	errIncorrectLabelMatch  = errors.New("label_match must be any or all")
+	errIncorrectSort        = errors.New("sort must be position or priority")
+	errIncorrectArchived    = errors.New("archived must be true or false")
+	errIncorrectMFAToken    = errors.New("incorrect mfa token")
+	errReadOnlyAPIKey       = errors.New("api key is read-only")
+	errAccountAPIKey        = errors.New("api keys can not change the account")
+	errNotAdmin             = errors.New("admin role required")
+	errDisableSelf          = errors.New("admins can not disable themselves")
+	errRateLimited          = errors.New("rate limit exceeded")
+	errOIDCDisabled         = errors.New("oidc login is not configured")
+	errIncorrectOIDCState   = errors.New("incorrect oidc state")
+	errOIDCEmailNotVerified = errors.New("identity provider has not verified the email")
 )
 
 type ctxKey uint8
 
+// The JWTs signed with the keys are told apart by their audience,
+// so a token of one kind is never accepted as another.
+const (
+	audienceToken     = "todo-app"
+	audienceOIDCState = "todo-app/oidc-state"
+)
+
+// oidcClaims keep the login started by handleOIDCLogin
+// in a cookie until the provider redirects back.
+type oidcClaims struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+// tokenClaims are the claims of the issued JWTs. An MFA pending token
+// only lets the user finish the login with the second factor.
+type tokenClaims struct {
+	UserID       int  `json:"user_id"`
+	TokenVersion int  `json:"token_version"`
+	MFAPending   bool `json:"mfa_pending,omitempty"`
+	jwt.RegisteredClaims
+}
+
 type server struct {
-	config *Config
-	logger *logrus.Logger
-	router *mux.Router
-	uc     usecase.UseCase
+	config  *Config
+	logger  *logrus.Logger
+	router  *mux.Router
+	uc      usecase.UseCase
+	keys    *signing.KeySet
+	oidc    *oidc.Provider
+	limiter *ratelimit.Limiter
+	health  *health.Checker
+	proxies []netip.Prefix
 }
 
-func NewServer(config *Config, uc usecase.UseCase) *server {
+func NewServer(config *Config, uc usecase.UseCase, keys *signing.KeySet) *server {
 	s := &server{
 		config: config,
 		logger: logrus.New(),
 		router: mux.NewRouter(),
 		uc:     uc,
+		keys:   keys,
+		health: health.NewChecker(),
 	}
 
 	s.configureRouter()
@@ -51,6 +102,34 @@ func NewServer(config *Config, uc usecase.UseCase) *server {
 	return s
 }
 
+// WithOIDC lets users sign in with the OIDC provider,
+// without it the OIDC endpoints respond with 404.
+func (s *server) WithOIDC(p *oidc.Provider) *server {
+	s.oidc = p
+	return s
+}
+
+// WithHealth makes /readyz run the checks of the checker,
+// without it only the shutdown is checked.
+func (s *server) WithHealth(c *health.Checker) *server {
+	s.health = c
+	return s
+}
+
+// WithTrustedProxies takes the client IP from the X-Forwarded-For or X-Real-IP
+// headers of requests coming from the proxies, without it the headers are ignored.
+func (s *server) WithTrustedProxies(proxies []netip.Prefix) *server {
+	s.proxies = proxies
+	return s
+}
+
+// WithRateLimiter limits the requests of every client to each route group,
+// without it the requests are not limited.
+func (s *server) WithRateLimiter(l *ratelimit.Limiter) *server {
+	s.limiter = l
+	return s
+}
+
 func (s *server) configureRouter() {
 
 	// middleware
@@ -61,19 +140,122 @@ func (s *server) configureRouter() {
 	// test
 	s.router.HandleFunc("/hello", s.handleHello()).Methods(http.MethodGet)
 
+	// probes
+	s.router.HandleFunc("/healthz", s.handleHealthz()).Methods(http.MethodGet)
+	s.router.HandleFunc("/readyz", s.handleReadyz()).Methods(http.MethodGet)
+
 	// public
-	s.router.HandleFunc("/users", s.handleUsersCreate()).Methods(http.MethodPost)
-	s.router.HandleFunc("/tokens", s.handleTokensCreate()).Methods(http.MethodPost)
+	public := s.rateLimit(ratelimit.GroupPublic)
+	s.router.Handle("/.well-known/jwks.json", public(s.handleJWKS())).Methods(http.MethodGet)
+	s.router.Handle("/users", public(s.handleUsersCreate())).Methods(http.MethodPost)
+	s.router.Handle("/tokens", public(s.handleTokensCreate())).Methods(http.MethodPost)
+	s.router.Handle("/tokens/mfa", public(s.handleTokensMFA())).Methods(http.MethodPost)
+	s.router.Handle("/oidc/login", public(s.handleOIDCLogin())).Methods(http.MethodGet)
+	s.router.Handle("/oidc/callback", public(s.handleOIDCCallback())).Methods(http.MethodGet)
+	s.router.Handle("/users/verify", public(s.handleEmailVerificationsConfirm())).Methods(http.MethodPost)
+	s.router.Handle("/password-resets", public(s.handlePasswordResetsCreate())).Methods(http.MethodPost)
+	s.router.Handle("/password-resets/{token}", public(s.handlePasswordResetsConfirm())).Methods(http.MethodPost)
 
 	// private
-	s.router.Handle("/profile", s.authenticateUser(s.handleUserProfile())).Methods(http.MethodGet)
+	profileSubrouter := s.router.PathPrefix("/profile").Subrouter()
+	profileSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	profileSubrouter.Use(s.authenticateUser)
+	profileSubrouter.Use(s.rateLimit("profile"))
+	profileSubrouter.HandleFunc("", s.handleUserProfile()).Methods(http.MethodGet)
+	profileSubrouter.Handle("", s.rejectAPIKey(s.handleUsersDelete())).Methods(http.MethodDelete)
+	profileSubrouter.Handle("/timezone", s.rejectAPIKey(s.handleUserTimezoneEdit())).Methods(http.MethodPut)
+	profileSubrouter.Handle("/password", s.rejectAPIKey(s.handleUserPasswordEdit())).Methods(http.MethodPut)
+	profileSubrouter.Handle("/email", s.rejectAPIKey(s.handleUserEmailEdit())).Methods(http.MethodPut)
+	profileSubrouter.Handle("/verification", s.rejectAPIKey(s.handleEmailVerificationsSend())).Methods(http.MethodPost)
+	profileSubrouter.Handle("/mfa", s.rejectAPIKey(s.handleMFAEnroll())).Methods(http.MethodPost)
+	profileSubrouter.Handle("/mfa/confirm", s.rejectAPIKey(s.handleMFAConfirm())).Methods(http.MethodPost)
+	profileSubrouter.Handle("/mfa", s.rejectAPIKey(s.handleMFADisable())).Methods(http.MethodDelete)
 
 	listSubrouter := s.router.PathPrefix("/lists").Subrouter()
+	listSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
 	listSubrouter.Use(s.authenticateUser)
+	listSubrouter.Use(s.rateLimit("lists"))
 	listSubrouter.HandleFunc("", s.handleListsCreate()).Methods(http.MethodPost)
 	listSubrouter.HandleFunc("", s.handleListsGetByUser()).Methods(http.MethodGet)
 	listSubrouter.HandleFunc("/{listID:[0-9]+}", s.handleListsGetByID()).Methods(http.MethodGet)
 	listSubrouter.HandleFunc("/{listID:[0-9]+}", s.handleListsEdit()).Methods(http.MethodPut)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/move", s.handleListsMove()).Methods(http.MethodPost)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/archive", s.handleListsArchive()).Methods(http.MethodPost)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/unarchive", s.handleListsUnarchive()).Methods(http.MethodPost)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/tasks", s.handleTasksCreate()).Methods(http.MethodPost)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/tasks", s.handleTasksGetByList()).Methods(http.MethodGet)
+	listSubrouter.HandleFunc("/{listID:[0-9]+}/template", s.handleTemplatesCreate()).Methods(http.MethodPost)
+
+	apiKeySubrouter := s.router.PathPrefix("/api-keys").Subrouter()
+	apiKeySubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	apiKeySubrouter.Use(s.authenticateUser)
+	apiKeySubrouter.Use(s.rateLimit("api-keys"))
+	apiKeySubrouter.Handle("", s.rejectAPIKey(s.handleAPIKeysCreate())).Methods(http.MethodPost)
+	apiKeySubrouter.HandleFunc("", s.handleAPIKeysGetByUser()).Methods(http.MethodGet)
+	apiKeySubrouter.HandleFunc("/{apiKeyID:[0-9]+}", s.handleAPIKeysGetByID()).Methods(http.MethodGet)
+	apiKeySubrouter.HandleFunc("/{apiKeyID:[0-9]+}", s.handleAPIKeysDelete()).Methods(http.MethodDelete)
+
+	adminSubrouter := s.router.PathPrefix("/admin").Subrouter()
+	adminSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	adminSubrouter.Use(s.authenticateUser)
+	adminSubrouter.Use(s.authorizeAdmin)
+	adminSubrouter.Use(s.rateLimit("admin"))
+	adminSubrouter.HandleFunc("/users", s.handleAdminUsersGet()).Methods(http.MethodGet)
+	adminSubrouter.HandleFunc("/users/{userID:[0-9]+}", s.handleAdminUsersGetByID()).Methods(http.MethodGet)
+	adminSubrouter.HandleFunc("/users/{userID:[0-9]+}/disable", s.handleAdminUsersDisable()).Methods(http.MethodPost)
+	adminSubrouter.HandleFunc("/users/{userID:[0-9]+}/enable", s.handleAdminUsersEnable()).Methods(http.MethodPost)
+	adminSubrouter.HandleFunc("/users/{userID:[0-9]+}/logout", s.handleAdminUsersLogout()).Methods(http.MethodPost)
+
+	templateSubrouter := s.router.PathPrefix("/templates").Subrouter()
+	templateSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	templateSubrouter.Use(s.authenticateUser)
+	templateSubrouter.Use(s.rateLimit("templates"))
+	templateSubrouter.HandleFunc("", s.handleTemplatesGetByUser()).Methods(http.MethodGet)
+	templateSubrouter.HandleFunc("/{templateID:[0-9]+}", s.handleTemplatesGetByID()).Methods(http.MethodGet)
+	templateSubrouter.HandleFunc("/{templateID:[0-9]+}", s.handleTemplatesDelete()).Methods(http.MethodDelete)
+
+	labelSubrouter := s.router.PathPrefix("/labels").Subrouter()
+	labelSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	labelSubrouter.Use(s.authenticateUser)
+	labelSubrouter.Use(s.rateLimit("labels"))
+	labelSubrouter.HandleFunc("", s.handleLabelsCreate()).Methods(http.MethodPost)
+	labelSubrouter.HandleFunc("", s.handleLabelsGetByUser()).Methods(http.MethodGet)
+	labelSubrouter.HandleFunc("/{labelID:[0-9]+}", s.handleLabelsGetByID()).Methods(http.MethodGet)
+	labelSubrouter.HandleFunc("/{labelID:[0-9]+}", s.handleLabelsEdit()).Methods(http.MethodPut)
+	labelSubrouter.HandleFunc("/{labelID:[0-9]+}", s.handleLabelsDelete()).Methods(http.MethodDelete)
+
+	viewSubrouter := s.router.PathPrefix("/views").Subrouter()
+	viewSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	viewSubrouter.Use(s.authenticateUser)
+	viewSubrouter.Use(s.rateLimit("views"))
+	viewSubrouter.HandleFunc("", s.handleViewsCreate()).Methods(http.MethodPost)
+	viewSubrouter.HandleFunc("", s.handleViewsGetByUser()).Methods(http.MethodGet)
+	viewSubrouter.HandleFunc("/{viewID:[0-9]+}", s.handleViewsGetByID()).Methods(http.MethodGet)
+	viewSubrouter.HandleFunc("/{viewID:[0-9]+}", s.handleViewsEdit()).Methods(http.MethodPut)
+	viewSubrouter.HandleFunc("/{viewID:[0-9]+}", s.handleViewsDelete()).Methods(http.MethodDelete)
+	viewSubrouter.HandleFunc("/{view}/tasks", s.handleViewsGetTasks()).Methods(http.MethodGet)
+
+	taskSubrouter := s.router.PathPrefix("/tasks").Subrouter()
+	taskSubrouter.Use(s.rateLimit(ratelimit.GroupAuth))
+	taskSubrouter.Use(s.authenticateUser)
+	taskSubrouter.Use(s.rateLimit("tasks"))
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}", s.handleTasksGetByID()).Methods(http.MethodGet)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}", s.handleTasksEdit()).Methods(http.MethodPut)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}", s.handleTasksDelete()).Methods(http.MethodDelete)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/move", s.handleTasksMove()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/items", s.handleItemsCreate()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/items", s.handleItemsGetByTask()).Methods(http.MethodGet)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/items/order", s.handleItemsReorder()).Methods(http.MethodPut)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/items/{itemID:[0-9]+}/toggle", s.handleItemsToggle()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/items/{itemID:[0-9]+}", s.handleItemsDelete()).Methods(http.MethodDelete)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/dependencies", s.handleDependenciesCreate()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/dependencies", s.handleDependenciesGetByTask()).Methods(http.MethodGet)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/dependencies/{blockerID:[0-9]+}", s.handleDependenciesDelete()).Methods(http.MethodDelete)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/labels", s.handleTaskLabelsAttach()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/reminders", s.handleRemindersCreate()).Methods(http.MethodPost)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/reminders", s.handleRemindersGetByTask()).Methods(http.MethodGet)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/reminders/{reminderID:[0-9]+}", s.handleRemindersDelete()).Methods(http.MethodDelete)
+	taskSubrouter.HandleFunc("/{taskID:[0-9]+}/labels/{labelID:[0-9]+}", s.handleTaskLabelsDetach()).Methods(http.MethodDelete)
 }
 
 func (s *server) configureLogger() error {
@@ -85,14 +267,54 @@ func (s *server) configureLogger() error {
 	return nil
 }
 
-func (s *server) StartServer() error {
+// StartServer serves the API until the context is done, then fails
+// the readiness check for shutdown_delay, stops accepting connections
+// and waits for the requests in flight for up to shutdown_timeout.
+func (s *server) StartServer(ctx context.Context) error {
 	if err := s.configureLogger(); err != nil {
 		return err
 	}
 
-	s.logger.Info("starting api server")
+	srv := &http.Server{
+		Addr:              s.config.BindAddr,
+		Handler:           s,
+		ReadHeaderTimeout: s.config.ReadHeaderTimeout,
+		ReadTimeout:       s.config.ReadTimeout,
+		WriteTimeout:      s.config.WriteTimeout,
+		IdleTimeout:       s.config.IdleTimeout,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.logger.Info("starting api server")
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("shutting down api server")
+
+	// let the load balancer see that the server is not ready
+	// before it stops accepting connections
+	s.health.Shutdown()
+	time.Sleep(s.config.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
 
-	return http.ListenAndServe(s.config.BindAddr, s)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return err
+	}
+
+	if err := <-errs; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -105,6 +327,29 @@ func (s *server) handleHello() http.HandlerFunc {
 	}
 }
 
+// handleHealthz tells that the process is alive.
+func (s *server) handleHealthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, r, http.StatusOK, &health.Report{Status: health.StatusOK})
+	}
+}
+
+// handleReadyz tells whether the server can serve requests,
+// with the result of each check.
+func (s *server) handleReadyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		report := s.health.Ready(r.Context())
+		if !report.OK() {
+			s.respond(w, r, http.StatusServiceUnavailable, report)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, report)
+	}
+}
+
 func (s *server) setRequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := uuid.New().String()
@@ -146,11 +391,6 @@ func (s *server) logRequest(next http.Handler) http.Handler {
 }
 
 func (s *server) authenticateUser(next http.Handler) http.Handler {
-	type tokenClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
-	}
-
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader, ok := r.Header["Authorization"]
 		if !ok {
@@ -166,26 +406,36 @@ func (s *server) authenticateUser(next http.Handler) http.Handler {
 
 		tokenString := authHeaderParts[1]
 
-		token, err := jwt.ParseWithClaims(
-			tokenString,
-			&tokenClaims{},
-			func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, errors.New("incorrect signing method")
-				}
-				return []byte(s.config.SecretKey), nil
-			})
+		if entity.IsAPIKey(tokenString) {
+			u, k, err := s.uc.APIKeysAuthenticate(tokenString)
+			if err != nil {
+				s.error(w, r, http.StatusUnauthorized, errNotAuthenticated)
+				return
+			}
+
+			if u.Disabled() {
+				s.error(w, r, http.StatusForbidden, usecase.ErrAccountDisabled)
+				return
+			}
+
+			if !k.Allows(r.Method) {
+				s.error(w, r, http.StatusForbidden, errReadOnlyAPIKey)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ctxKeyUser, u)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, ctxKeyAPIKey, k)))
+			return
+		}
 
+		u, err := s.parseToken(tokenString, false)
 		if err != nil {
 			s.error(w, r, http.StatusUnauthorized, errNotAuthenticated)
 			return
 		}
 
-		claims := token.Claims.(*tokenClaims)
-
-		u, err := s.uc.UsersFindByID(claims.UserID)
-		if err != nil {
-			s.error(w, r, http.StatusUnauthorized, errNotAuthenticated)
+		if u.Disabled() {
+			s.error(w, r, http.StatusForbidden, usecase.ErrAccountDisabled)
 			return
 		}
 
@@ -193,10 +443,133 @@ func (s *server) authenticateUser(next http.Handler) http.Handler {
 	})
 }
 
+// authorizeAdmin lets through the admins authenticated by authenticateUser.
+// API keys can't be used for the admin API.
+func (s *server) authorizeAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+		if !u.IsAdmin() || r.Context().Value(ctxKeyAPIKey) != nil {
+			s.error(w, r, http.StatusForbidden, errNotAdmin)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectAPIKey guards the account routes, which only a user authenticated
+// by authenticateUser with a token may use. A leaked key must not be able
+// to take over the account. Keys may only read the profile.
+func (s *server) rejectAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Value(ctxKeyAPIKey) != nil {
+			s.error(w, r, http.StatusForbidden, errAccountAPIKey)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimit limits the requests to the route group by the user
+// authenticated by authenticateUser, or by the IP if there is none.
+// The requests are let through if the limiter fails.
+func (s *server) rateLimit(group string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := "ip:" + s.clientIP(r)
+			if u, ok := r.Context().Value(ctxKeyUser).(*entity.User); ok {
+				client = "user:" + strconv.Itoa(u.UserID)
+			}
+
+			res, err := s.limiter.Allow(group, client)
+			if err != nil {
+				s.logger.WithFields(logrus.Fields{
+					"request_id": r.Context().Value(ctxKeyRequestID),
+					"group":      group,
+				}).Errorf("rate limiter failed: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if res == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				s.error(w, r, http.StatusTooManyRequests, errRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseToken returns the user of a valid token issued by issueToken,
+// the token must be MFA pending or not as asked.
+func (s *server) parseToken(tokenString string, mfaPending bool) (*entity.User, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, s.keys.Keyfunc, jwt.WithAudience(audienceToken))
+	if err != nil {
+		return nil, err
+	}
+
+	claims := token.Claims.(*tokenClaims)
+	if claims.MFAPending != mfaPending {
+		return nil, errNotAuthenticated
+	}
+
+	u, err := s.uc.UsersFindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TokenVersion != claims.TokenVersion {
+		return nil, errNotAuthenticated
+	}
+	return u, nil
+}
+
+// issueToken signs a token of the user valid for five minutes.
+func (s *server) issueToken(u *entity.User, mfaPending bool) (string, error) {
+	claims := &tokenClaims{
+		UserID:       u.UserID,
+		TokenVersion: u.TokenVersion,
+		MFAPending:   mfaPending,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{audienceToken},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+		},
+	}
+
+	return s.keys.Sign(claims)
+}
+
+// handleJWKS publishes the public keys tokens are signed with,
+// so other services can verify them.
+func (s *server) handleJWKS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		s.respond(w, r, http.StatusOK, s.keys.JWKS())
+	}
+}
+
 func (s *server) handleUsersCreate() http.HandlerFunc {
 	type request struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Timezone string `json:"timezone"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -209,6 +582,7 @@ func (s *server) handleUsersCreate() http.HandlerFunc {
 		u := &entity.User{
 			Email:    req.Email,
 			Password: req.Password,
+			Timezone: req.Timezone,
 		}
 
 		if err := s.uc.UsersCreate(u); err != nil {
@@ -216,6 +590,11 @@ func (s *server) handleUsersCreate() http.HandlerFunc {
 			return
 		}
 
+		// the user can ask for another email, so signing up doesn't fail
+		if err := s.uc.EmailVerificationsSend(u); err != nil {
+			s.logger.WithField("request_id", r.Context().Value(ctxKeyRequestID)).Errorf("email verification: %v", err)
+		}
+
 		u.Sanitize()
 		s.respond(w, r, http.StatusCreated, u)
 	}
@@ -227,11 +606,6 @@ func (s *server) handleTokensCreate() http.HandlerFunc {
 		Password string `json:"password"`
 	}
 
-	type tokenClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
-	}
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		req := &request{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
@@ -239,22 +613,154 @@ func (s *server) handleTokensCreate() http.HandlerFunc {
 			return
 		}
 
-		u, err := s.uc.UsersFindByEmail(req.Email)
-		if err != nil || !u.ComparePassword(req.Password) {
-			s.error(w, r, http.StatusUnauthorized, errIncorrectEmailOrPassword)
+		ip := s.clientIP(r)
+		u, err := s.uc.Login(req.Email, req.Password, ip)
+		if err != nil {
+			var locked *usecase.LoginLockedError
+			switch {
+			case errors.As(err, &locked):
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(locked.RetryAfter)))
+				s.error(w, r, http.StatusTooManyRequests, err)
+			case err == usecase.ErrIncorrectEmailOrPassword:
+				s.logger.WithFields(logrus.Fields{
+					"email":      req.Email,
+					"ip":         ip,
+					"request_id": r.Context().Value(ctxKeyRequestID),
+				}).Warn("failed login")
+				s.error(w, r, http.StatusUnauthorized, err)
+			default:
+				s.error(w, r, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		s.login(w, r, u)
+	}
+}
+
+// clientIP returns the IP address the request came from. The forwarding headers
+// can be set by anyone, so they are only read if the request came from a trusted
+// proxy. X-Forwarded-For is read from the right, skipping the trusted proxies,
+// since a client can prepend addresses of its own.
+func (s *server) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !s.trustedProxy(host) {
+		return host
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for n := len(hops) - 1; n >= 0; n-- {
+			hop := strings.TrimSpace(hops[n])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+
+			host = hop
+			if !s.trustedProxy(hop) {
+				break
+			}
+		}
+		return host
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+	return host
+}
+
+func (s *server) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, p := range s.proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ceilSeconds rounds d up to whole seconds for the Retry-After
+// and RateLimit-Reset headers.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// login sets the token of the authenticated user, or responds with
+// an MFA pending token if the user has to give a second factor first.
+func (s *server) login(w http.ResponseWriter, r *http.Request, u *entity.User) {
+	type response struct {
+		MFARequired bool   `json:"mfa_required"`
+		MFAToken    string `json:"mfa_token"`
+	}
+
+	if u.Disabled() {
+		s.error(w, r, http.StatusForbidden, usecase.ErrAccountDisabled)
+		return
+	}
+
+	enabled, err := s.uc.MFAEnabled(u.UserID)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if enabled {
+		tokenString, err := s.issueToken(u, true)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, &response{MFARequired: true, MFAToken: tokenString})
+		return
+	}
+
+	s.setToken(w, r, u)
+}
+
+// handleOIDCLogin redirects to the provider, keeping the state, nonce
+// and PKCE verifier of the login in a signed cookie.
+func (s *server) handleOIDCLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.oidc == nil {
+			s.error(w, r, http.StatusNotFound, errOIDCDisabled)
 			return
 		}
 
-		exp := time.Now().Add(time.Minute * 5)
-		claims := &tokenClaims{
-			UserID: u.UserID,
+		claims := &oidcClaims{
 			RegisteredClaims: jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(exp),
+				Audience:  jwt.ClaimStrings{audienceOIDCState},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 10)),
 			},
 		}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		tokenString, err := token.SignedString([]byte(s.config.SecretKey))
+		var err error
+		for _, v := range []*string{&claims.State, &claims.Nonce, &claims.Verifier} {
+			if *v, err = entity.NewToken(); err != nil {
+				s.error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		authURL, err := s.oidc.AuthCodeURL(r.Context(), claims.State, claims.Nonce, claims.Verifier)
+		if err != nil {
+			s.error(w, r, http.StatusBadGateway, err)
+			return
+		}
+
+		cookie, err := s.keys.Sign(claims)
 		if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 			return
@@ -262,23 +768,81 @@ func (s *server) handleTokensCreate() http.HandlerFunc {
 
 		http.SetCookie(w,
 			&http.Cookie{
-				Name:  "token",
-				Value: tokenString,
+				Name:     "oidc",
+				Value:    cookie,
+				Path:     "/oidc",
+				MaxAge:   600,
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
 			})
 
-		s.respond(w, r, http.StatusOK, nil)
+		http.Redirect(w, r, authURL, http.StatusFound)
 	}
 }
 
-func (s *server) handleUserProfile() http.HandlerFunc {
+// handleOIDCCallback signs in the user with the email verified by the
+// provider, signing up a new user if there is none.
+func (s *server) handleOIDCCallback() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s.respond(w, r, http.StatusOK, r.Context().Value(ctxKeyUser))
+		if s.oidc == nil {
+			s.error(w, r, http.StatusNotFound, errOIDCDisabled)
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("error") != "" {
+			s.error(w, r, http.StatusUnauthorized, errors.New(q.Get("error")))
+			return
+		}
+
+		cookie, err := r.Cookie("oidc")
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectOIDCState)
+			return
+		}
+
+		token, err := jwt.ParseWithClaims(cookie.Value, &oidcClaims{}, s.keys.Keyfunc, jwt.WithAudience(audienceOIDCState))
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, errIncorrectOIDCState)
+			return
+		}
+
+		claims := token.Claims.(*oidcClaims)
+		if claims.State == "" || claims.State != q.Get("state") {
+			s.error(w, r, http.StatusBadRequest, errIncorrectOIDCState)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "oidc", Path: "/oidc", MaxAge: -1})
+
+		idClaims, err := s.oidc.Exchange(r.Context(), q.Get("code"), claims.Verifier, claims.Nonce)
+		if err != nil {
+			s.error(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		if idClaims.Email == "" || !idClaims.EmailVerified {
+			s.error(w, r, http.StatusForbidden, errOIDCEmailNotVerified)
+			return
+		}
+
+		u, err := s.uc.UsersFindOrCreateVerified(idClaims.Email)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.login(w, r, u)
 	}
 }
 
-func (s *server) handleListsCreate() http.HandlerFunc {
+// handleTokensMFA finishes the login of a user with 2FA
+// by a TOTP or recovery code.
+func (s *server) handleTokensMFA() http.HandlerFunc {
 	type request struct {
-		ListTitle string `json:"list_title"`
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -288,60 +852,103 @@ func (s *server) handleListsCreate() http.HandlerFunc {
 			return
 		}
 
-		u := r.Context().Value(ctxKeyUser).(*entity.User)
-
-		l := &entity.List{
-			ListTitle: req.ListTitle,
-			UserID:    u.UserID,
+		u, err := s.parseToken(req.MFAToken, true)
+		if err != nil {
+			s.error(w, r, http.StatusUnauthorized, errIncorrectMFAToken)
+			return
 		}
 
-		if err := s.uc.ListsCreate(l); err != nil {
-			s.error(w, r, http.StatusUnprocessableEntity, err)
+		ip := s.clientIP(r)
+		if err := s.uc.LoginMFA(u, req.Code, ip); err != nil {
+			var locked *usecase.LoginLockedError
+			switch {
+			case errors.As(err, &locked):
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(locked.RetryAfter)))
+				s.error(w, r, http.StatusTooManyRequests, err)
+			case err == usecase.ErrInvalidMFACode:
+				s.logger.WithFields(logrus.Fields{
+					"user_id":    u.UserID,
+					"ip":         ip,
+					"request_id": r.Context().Value(ctxKeyRequestID),
+				}).Warn("failed mfa")
+				s.error(w, r, http.StatusUnauthorized, err)
+			default:
+				s.error(w, r, http.StatusInternalServerError, err)
+			}
 			return
 		}
 
-		s.respond(w, r, http.StatusCreated, l)
+		s.setToken(w, r, u)
 	}
 }
 
-func (s *server) handleListsGetByUser() http.HandlerFunc {
+// setToken issues a token of the user and sets it as a cookie.
+func (s *server) setToken(w http.ResponseWriter, r *http.Request, u *entity.User) {
+	tokenString, err := s.issueToken(u, false)
+	if err != nil {
+		s.error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	http.SetCookie(w,
+		&http.Cookie{
+			Name:  "token",
+			Value: tokenString,
+		})
+
+	s.respond(w, r, http.StatusOK, nil)
+}
+
+func (s *server) handleMFAEnroll() http.HandlerFunc {
+	type response struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		u := r.Context().Value(ctxKeyUser).(*entity.User)
 
-		list, err := s.uc.ListsFindByUser(u.UserID)
+		secret, uri, err := s.uc.MFAEnroll(u)
 		if err != nil {
-			s.error(w, r, http.StatusInternalServerError, err)
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
 			return
 		}
 
-		s.respond(w, r, http.StatusOK, list)
+		s.respond(w, r, http.StatusOK, &response{Secret: secret, OTPAuthURI: uri})
 	}
 }
 
-func (s *server) handleListsGetByID() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		u := r.Context().Value(ctxKeyUser).(*entity.User)
+func (s *server) handleMFAConfirm() http.HandlerFunc {
+	type request struct {
+		Code string `json:"code"`
+	}
 
-		v := mux.Vars(r)
-		listID, err := strconv.Atoi(v["listID"])
-		if err != nil {
+	type response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 			s.error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		codes, err := s.uc.MFAConfirm(u, req.Code)
 		if err != nil {
-			s.error(w, r, http.StatusNotFound, err)
+			s.error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
 
-		s.respond(w, r, http.StatusOK, l)
+		s.respond(w, r, http.StatusOK, &response{RecoveryCodes: codes})
 	}
 }
 
-func (s *server) handleListsEdit() http.HandlerFunc {
+func (s *server) handleMFADisable() http.HandlerFunc {
 	type request struct {
-		ListTitle string `json:"list_title"`
+		Code string `json:"code"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -353,43 +960,1820 @@ func (s *server) handleListsEdit() http.HandlerFunc {
 
 		u := r.Context().Value(ctxKeyUser).(*entity.User)
 
-		v := mux.Vars(r)
-		listID, err := strconv.Atoi(v["listID"])
-		if err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.uc.MFADisable(u, req.Code); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
 
-		if _, err = s.uc.ListsFindByID(listID, u.UserID); err != nil {
-			s.error(w, r, http.StatusNotFound, err)
-			return
-		}
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
 
-		l := &entity.List{
-			ListID:    listID,
-			ListTitle: req.ListTitle,
-			UserID:    u.UserID,
+func (s *server) handleEmailVerificationsConfirm() http.HandlerFunc {
+	type request struct {
+		Token string `json:"token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
 		}
 
-		l, err = s.uc.ListsEdit(l)
-		if err != nil {
+		if err := s.uc.EmailVerificationsConfirm(req.Token); err != nil {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
 
-		s.respond(w, r, http.StatusOK, l)
+		s.respond(w, r, http.StatusNoContent, nil)
 	}
 }
 
-func (s *server) error(w http.ResponseWriter, r *http.Request, code int, err error) {
-	s.respond(w, r, code, map[string]string{"error": err.Error()})
-}
+func (s *server) handleEmailVerificationsSend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
 
-func (s *server) respond(w http.ResponseWriter, r *http.Request, code int, data interface{}) {
-	w.WriteHeader(code)
+		if err := s.uc.EmailVerificationsSend(u); err != nil {
+			s.error(w, r, statusOf(err, http.StatusUnprocessableEntity), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusAccepted, nil)
+	}
+}
+
+// handlePasswordResetsCreate always accepts the request,
+// so it doesn't tell which emails have an account.
+func (s *server) handlePasswordResetsCreate() http.HandlerFunc {
+	type request struct {
+		Email string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.uc.PasswordResetsCreate(req.Email); err != nil {
+			s.logger.WithField("request_id", r.Context().Value(ctxKeyRequestID)).Errorf("password reset: %v", err)
+		}
+
+		s.respond(w, r, http.StatusAccepted, nil)
+	}
+}
+
+func (s *server) handlePasswordResetsConfirm() http.HandlerFunc {
+	type request struct {
+		Password string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		v := mux.Vars(r)
+		if err := s.uc.PasswordResetsConfirm(v["token"], req.Password); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleUserProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, r, http.StatusOK, r.Context().Value(ctxKeyUser))
+	}
+}
+
+func (s *server) handleUserTimezoneEdit() http.HandlerFunc {
+	type request struct {
+		Timezone string `json:"timezone"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := *r.Context().Value(ctxKeyUser).(*entity.User)
+		u.Timezone = req.Timezone
+
+		edited, err := s.uc.UsersEditTimezone(&u)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, edited)
+	}
+}
+
+// handleUserPasswordEdit revokes the other sessions of the user
+// and sets a new token for this one.
+func (s *server) handleUserPasswordEdit() http.HandlerFunc {
+	type request struct {
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := *r.Context().Value(ctxKeyUser).(*entity.User)
+		if err := s.uc.UsersEditPassword(&u, req.CurrentPassword, req.Password); err != nil {
+			s.error(w, r, statusOf(err, http.StatusUnprocessableEntity), err)
+			return
+		}
+
+		s.setToken(w, r, &u)
+	}
+}
+
+// handleUserEmailEdit mails a verification to the new email.
+func (s *server) handleUserEmailEdit() http.HandlerFunc {
+	type request struct {
+		Password string `json:"password"`
+		Email    string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := *r.Context().Value(ctxKeyUser).(*entity.User)
+		if err := s.uc.UsersEditEmail(&u, req.Password, req.Email); err != nil {
+			s.error(w, r, statusOf(err, http.StatusUnprocessableEntity), err)
+			return
+		}
+
+		if err := s.uc.EmailVerificationsSend(&u); err != nil {
+			s.logger.WithField("request_id", r.Context().Value(ctxKeyRequestID)).Errorf("email verification: %v", err)
+		}
+
+		s.respond(w, r, http.StatusOK, &u)
+	}
+}
+
+func (s *server) handleUsersDelete() http.HandlerFunc {
+	type request struct {
+		Password string `json:"password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+		if err := s.uc.UsersDelete(u, req.Password); err != nil {
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleAdminUsersGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := s.uc.UsersFindAll()
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, users)
+	}
+}
+
+// handleAdminUsersGetByID shows the user with what the user has created.
+func (s *server) handleAdminUsersGetByID() http.HandlerFunc {
+	type response struct {
+		*entity.User
+		Usage *entity.Usage `json:"usage"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.adminTarget(w, r)
+		if !ok {
+			return
+		}
+
+		usage, err := s.uc.UsersUsage(u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, &response{User: u, Usage: usage})
+	}
+}
+
+func (s *server) handleAdminUsersDisable() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.adminTarget(w, r)
+		if !ok {
+			return
+		}
+
+		if u.UserID == r.Context().Value(ctxKeyUser).(*entity.User).UserID {
+			s.error(w, r, http.StatusUnprocessableEntity, errDisableSelf)
+			return
+		}
+
+		if err := s.uc.UsersDisable(u); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, u)
+	}
+}
+
+func (s *server) handleAdminUsersEnable() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.adminTarget(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.uc.UsersEnable(u); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, u)
+	}
+}
+
+// handleAdminUsersLogout ends the sessions of the user,
+// the API keys of the user keep working.
+func (s *server) handleAdminUsersLogout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.adminTarget(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.uc.UsersRevokeTokens(u); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+// adminTarget finds the user named in the path of an admin request,
+// responding with an error if there is none.
+func (s *server) adminTarget(w http.ResponseWriter, r *http.Request) (*entity.User, bool) {
+	userID, err := strconv.Atoi(mux.Vars(r)["userID"])
+	if err != nil {
+		s.error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	u, err := s.uc.UsersFindByID(userID)
+	if err != nil {
+		s.error(w, r, http.StatusNotFound, err)
+		return nil, false
+	}
+
+	u.Sanitize()
+	return u, true
+}
+
+// handleAPIKeysCreate shows the new key once, so the user must save it.
+// Only a user logged in with a token may create keys, a key can't create
+// another key to outlive its own expiry or revocation.
+func (s *server) handleAPIKeysCreate() http.HandlerFunc {
+	type request struct {
+		Name      string          `json:"name"`
+		Scope     string          `json:"scope"`
+		ExpiresAt *entity.TimeISO `json:"expires_at"`
+	}
+
+	type response struct {
+		*entity.APIKey
+		Key string `json:"key"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		k := &entity.APIKey{
+			Name:      req.Name,
+			Scope:     req.Scope,
+			ExpiresAt: req.ExpiresAt,
+			UserID:    u.UserID,
+		}
+
+		key, err := s.uc.APIKeysCreate(k)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, &response{APIKey: k, Key: key})
+	}
+}
+
+func (s *server) handleAPIKeysGetByUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		keys, err := s.uc.APIKeysFindByUser(u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, keys)
+	}
+}
+
+func (s *server) handleAPIKeysGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		apiKeyID, err := strconv.Atoi(v["apiKeyID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		k, err := s.uc.APIKeysFindByID(apiKeyID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, k)
+	}
+}
+
+func (s *server) handleAPIKeysDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		apiKeyID, err := strconv.Atoi(v["apiKeyID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		k, err := s.uc.APIKeysFindByID(apiKeyID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.APIKeysDelete(k); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleListsCreate() http.HandlerFunc {
+	type request struct {
+		ListTitle string `json:"list_title"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		l := &entity.List{
+			ListTitle: req.ListTitle,
+			UserID:    u.UserID,
+		}
+
+		q := r.URL.Query()
+		if q.Get("from_template") == "" {
+			if err := s.uc.ListsCreate(l); err != nil {
+				s.error(w, r, statusOf(err, http.StatusUnprocessableEntity), err)
+				return
+			}
+
+			s.respond(w, r, http.StatusCreated, l)
+			return
+		}
+
+		templateID, err := strconv.Atoi(q.Get("from_template"))
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		start := entity.Today(u.Location())
+		if q.Get("start") != "" {
+			start, err = entity.ParseDate(q.Get("start"))
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		t, err := s.uc.TemplatesFindByID(templateID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.ListsCreateFromTemplate(l, t, start, u.Location()); err != nil {
+			s.error(w, r, statusOf(err, http.StatusUnprocessableEntity), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, l)
+	}
+}
+
+func (s *server) handleListsGetByUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		archived := false
+		if value := r.URL.Query().Get("archived"); value != "" {
+			var err error
+			archived, err = strconv.ParseBool(value)
+			if err != nil {
+				s.error(w, r, http.StatusBadRequest, errIncorrectArchived)
+				return
+			}
+		}
+
+		list, err := s.uc.ListsFindByUser(u.UserID, archived)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, list)
+	}
+}
+
+func (s *server) handleListsGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleListsEdit() http.HandlerFunc {
+	type request struct {
+		ListTitle string `json:"list_title"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		l = &entity.List{
+			ListID:     listID,
+			ListTitle:  req.ListTitle,
+			UserID:     u.UserID,
+			Position:   l.Position,
+			ArchivedAt: l.ArchivedAt,
+		}
+
+		l, err = s.uc.ListsEdit(l)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleListsMove() http.HandlerFunc {
+	type request struct {
+		AfterID  int `json:"after_id"`
+		BeforeID int `json:"before_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		l, err = s.uc.ListsMove(l, req.AfterID, req.BeforeID)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleListsArchive() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		l, err = s.uc.ListsArchive(l)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleListsUnarchive() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		l, err = s.uc.ListsUnarchive(l)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleTasksCreate() http.HandlerFunc {
+	type request struct {
+		TaskTitle string          `json:"task_title"`
+		Details   string          `json:"details"`
+		Deadline  *entity.TimeISO `json:"deadline"`
+		DueDate   *entity.Date    `json:"due_date"`
+		Priority  entity.Priority `json:"priority"`
+		Estimate  *int            `json:"estimate"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.ListsFindByID(listID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		t := &entity.Task{
+			TaskTitle: req.TaskTitle,
+			Details:   req.Details,
+			Deadline:  req.Deadline,
+			DueDate:   req.DueDate,
+			Priority:  req.Priority,
+			Estimate:  req.Estimate,
+			ListID:    listID,
+		}
+
+		if err := s.uc.TasksCreate(t); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		t.Localize(u.Location())
+		s.respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *server) handleTasksGetByList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.ListsFindByID(listID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		f := &entity.TaskFilter{
+			ListID: listID,
+		}
+
+		q := r.URL.Query()
+		f.LabelIDs, err = parseIDs(q["label"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		switch q.Get("label_match") {
+		case "", "any":
+		case "all":
+			f.AllLabels = true
+		default:
+			s.error(w, r, http.StatusBadRequest, errIncorrectLabelMatch)
+			return
+		}
+
+		switch q.Get("sort") {
+		case "", entity.TaskSortPosition:
+		case entity.TaskSortPriority:
+			f.Sort = entity.TaskSortPriority
+		default:
+			s.error(w, r, http.StatusBadRequest, errIncorrectSort)
+			return
+		}
+
+		tasks, err := s.uc.TasksFindByFilter(f)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, t := range tasks {
+			t.Localize(u.Location())
+		}
+		s.respond(w, r, http.StatusOK, tasks)
+	}
+}
+
+func (s *server) handleTasksGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TasksFindByID(taskID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		t.Localize(u.Location())
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleTasksEdit() http.HandlerFunc {
+	type request struct {
+		TaskTitle string          `json:"task_title"`
+		Details   string          `json:"details"`
+		Deadline  *entity.TimeISO `json:"deadline"`
+		DueDate   *entity.Date    `json:"due_date"`
+		Done      bool            `json:"done"`
+		Priority  entity.Priority `json:"priority"`
+		Estimate  *int            `json:"estimate"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TasksFindByID(taskID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		t = &entity.Task{
+			TaskID:    taskID,
+			TaskTitle: req.TaskTitle,
+			Details:   req.Details,
+			Deadline:  req.Deadline,
+			DueDate:   req.DueDate,
+			Done:      req.Done,
+			Priority:  req.Priority,
+			Estimate:  req.Estimate,
+			ListID:    t.ListID,
+			Position:  t.Position,
+		}
+
+		t, err = s.uc.TasksEdit(t)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		t.Localize(u.Location())
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleTasksDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TasksFindByID(taskID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.TasksDelete(t); err != nil {
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleTasksMove() http.HandlerFunc {
+	type request struct {
+		AfterID  int `json:"after_id"`
+		BeforeID int `json:"before_id"`
+		ListID   int `json:"list_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TasksFindByID(taskID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if req.ListID != 0 {
+			if _, err := s.uc.ListsFindByID(req.ListID, u.UserID); err != nil {
+				s.error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+		}
+
+		t, err = s.uc.TasksMove(t, req.ListID, req.AfterID, req.BeforeID)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		t.Localize(u.Location())
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleItemsCreate() http.HandlerFunc {
+	type request struct {
+		ItemTitle string `json:"item_title"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		i := &entity.Item{
+			ItemTitle: req.ItemTitle,
+			TaskID:    taskID,
+		}
+
+		if err := s.uc.ItemsCreate(i); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, i)
+	}
+}
+
+func (s *server) handleItemsGetByTask() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		items, err := s.uc.ItemsFindByTask(taskID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, items)
+	}
+}
+
+func (s *server) handleItemsReorder() http.HandlerFunc {
+	type request struct {
+		ItemIDs []int `json:"item_ids"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		items, err := s.uc.ItemsReorder(taskID, req.ItemIDs)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, items)
+	}
+}
+
+func (s *server) handleItemsToggle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		itemID, err := strconv.Atoi(v["itemID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		i, err := s.uc.ItemsFindByID(itemID, taskID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		i, err = s.uc.ItemsToggle(i)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, i)
+	}
+}
+
+func (s *server) handleItemsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		itemID, err := strconv.Atoi(v["itemID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		i, err := s.uc.ItemsFindByID(itemID, taskID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.ItemsDelete(i); err != nil {
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleRemindersCreate() http.HandlerFunc {
+	type request struct {
+		RemindAt *entity.TimeISO `json:"remind_at"`
+		Offset   *int            `json:"offset"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		rm := &entity.Reminder{
+			TaskID:   taskID,
+			RemindAt: req.RemindAt,
+			Offset:   req.Offset,
+		}
+
+		if err := s.uc.RemindersCreate(rm); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		rm.Localize(u.Location())
+		s.respond(w, r, http.StatusCreated, rm)
+	}
+}
+
+func (s *server) handleRemindersGetByTask() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		reminders, err := s.uc.RemindersFindByTask(taskID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, rm := range reminders {
+			rm.Localize(u.Location())
+		}
+		s.respond(w, r, http.StatusOK, reminders)
+	}
+}
+
+func (s *server) handleRemindersDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		reminderID, err := strconv.Atoi(v["reminderID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		rm, err := s.uc.RemindersFindByID(reminderID, taskID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.RemindersDelete(rm); err != nil {
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleDependenciesCreate() http.HandlerFunc {
+	type request struct {
+		BlockerID int `json:"blocker_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(req.BlockerID, u.UserID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		d := &entity.Dependency{
+			TaskID:    taskID,
+			BlockerID: req.BlockerID,
+		}
+
+		if err := s.uc.DependenciesCreate(d); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, d)
+	}
+}
+
+func (s *server) handleDependenciesGetByTask() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		dependencies, err := s.uc.DependenciesFindByTask(taskID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, dependencies)
+	}
+}
+
+func (s *server) handleDependenciesDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		blockerID, err := strconv.Atoi(v["blockerID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		d := &entity.Dependency{
+			TaskID:    taskID,
+			BlockerID: blockerID,
+		}
+
+		if err := s.uc.DependenciesDelete(d); err != nil {
+			s.error(w, r, statusOf(err, http.StatusNotFound), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleTemplatesCreate() http.HandlerFunc {
+	type request struct {
+		TemplateTitle string `json:"template_title"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		listID, err := strconv.Atoi(v["listID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.ListsFindByID(listID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		t := &entity.Template{
+			TemplateTitle: req.TemplateTitle,
+			UserID:        u.UserID,
+		}
+
+		if err := s.uc.TemplatesCreate(t, l, u.Location()); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *server) handleTemplatesGetByUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		templates, err := s.uc.TemplatesFindByUser(u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, templates)
+	}
+}
+
+func (s *server) handleTemplatesGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		templateID, err := strconv.Atoi(v["templateID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TemplatesFindByID(templateID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleTemplatesDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		templateID, err := strconv.Atoi(v["templateID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t, err := s.uc.TemplatesFindByID(templateID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.TemplatesDelete(t); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleLabelsCreate() http.HandlerFunc {
+	type request struct {
+		LabelTitle string `json:"label_title"`
+		Color      string `json:"color"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		l := &entity.Label{
+			LabelTitle: req.LabelTitle,
+			Color:      req.Color,
+			UserID:     u.UserID,
+		}
+
+		if err := s.uc.LabelsCreate(l); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, l)
+	}
+}
+
+func (s *server) handleLabelsGetByUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		labels, err := s.uc.LabelsFindByUser(u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, labels)
+	}
+}
+
+func (s *server) handleLabelsGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		labelID, err := strconv.Atoi(v["labelID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.LabelsFindByID(labelID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleLabelsEdit() http.HandlerFunc {
+	type request struct {
+		LabelTitle string `json:"label_title"`
+		Color      string `json:"color"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		labelID, err := strconv.Atoi(v["labelID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.LabelsFindByID(labelID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		l := &entity.Label{
+			LabelID:    labelID,
+			LabelTitle: req.LabelTitle,
+			Color:      req.Color,
+			UserID:     u.UserID,
+		}
+
+		l, err = s.uc.LabelsEdit(l)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *server) handleLabelsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		labelID, err := strconv.Atoi(v["labelID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l, err := s.uc.LabelsFindByID(labelID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.LabelsDelete(l); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleTaskLabelsAttach() http.HandlerFunc {
+	type request struct {
+		LabelID int `json:"label_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if _, err = s.uc.LabelsFindByID(req.LabelID, u.UserID); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := s.uc.LabelsAttach(taskID, req.LabelID); err != nil {
+			s.error(w, r, statusOf(err, http.StatusInternalServerError), err)
+			return
+		}
+
+		t, err := s.uc.TasksFindByID(taskID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		t.Localize(u.Location())
+		s.respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *server) handleTaskLabelsDetach() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		taskID, err := strconv.Atoi(v["taskID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		labelID, err := strconv.Atoi(v["labelID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.TasksFindByID(taskID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.LabelsDetach(taskID, labelID); err != nil {
+			s.error(w, r, statusOf(err, http.StatusNotFound), err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleViewsCreate() http.HandlerFunc {
+	type request struct {
+		ViewTitle string             `json:"view_title"`
+		Filter    *entity.TaskFilter `json:"filter"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := &entity.View{
+			ViewTitle: req.ViewTitle,
+			Filter:    req.Filter,
+			UserID:    u.UserID,
+		}
+
+		if err := s.uc.ViewsCreate(v); err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusCreated, v)
+	}
+}
+
+func (s *server) handleViewsGetByUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		views, err := s.uc.ViewsFindByUser(u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, views)
+	}
+}
+
+func (s *server) handleViewsGetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		viewID, err := strconv.Atoi(v["viewID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		view, err := s.uc.ViewsFindByID(viewID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, view)
+	}
+}
+
+func (s *server) handleViewsEdit() http.HandlerFunc {
+	type request struct {
+		ViewTitle string             `json:"view_title"`
+		Filter    *entity.TaskFilter `json:"filter"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		viewID, err := strconv.Atoi(v["viewID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err = s.uc.ViewsFindByID(viewID, u.UserID); err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		view := &entity.View{
+			ViewID:    viewID,
+			ViewTitle: req.ViewTitle,
+			Filter:    req.Filter,
+			UserID:    u.UserID,
+		}
+
+		view, err = s.uc.ViewsEdit(view)
+		if err != nil {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, view)
+	}
+}
+
+func (s *server) handleViewsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		viewID, err := strconv.Atoi(v["viewID"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		view, err := s.uc.ViewsFindByID(viewID, u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if err := s.uc.ViewsDelete(view); err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+func (s *server) handleViewsGetTasks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(ctxKeyUser).(*entity.User)
+
+		v := mux.Vars(r)
+		view, err := s.uc.ViewsFindByKey(v["view"], u.UserID)
+		if err != nil {
+			s.error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		tasks, err := s.uc.ViewsTasks(view, u)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, t := range tasks {
+			t.Localize(u.Location())
+		}
+		s.respond(w, r, http.StatusOK, tasks)
+	}
+}
+
+func (s *server) error(w http.ResponseWriter, r *http.Request, code int, err error) {
+	s.respond(w, r, code, map[string]string{"error": err.Error()})
+}
+
+func (s *server) respond(w http.ResponseWriter, r *http.Request, code int, data interface{}) {
+	w.WriteHeader(code)
 	if data != nil {
 		enc := json.NewEncoder(w)
 		enc.SetIndent("", "    ")
 		enc.Encode(data)
 	}
 }
+
+// parseIDs reads ids given as repeated or comma separated query values.
+func parseIDs(values []string) ([]int, error) {
+	ids := make([]int, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// statusOf reports refused changes to archived lists and MFA state
+// conflicts as unprocessable, unverified users as forbidden, too frequent
+// verification emails as too many requests and other errors with the
+// given status.
+func statusOf(err error, status int) int {
+	switch err {
+	case usecase.ErrListArchived, usecase.ErrMFAEnabled:
+		return http.StatusUnprocessableEntity
+	case usecase.ErrEmailNotVerified, usecase.ErrIncorrectPassword:
+		return http.StatusForbidden
+	case usecase.ErrVerificationTooSoon:
+		return http.StatusTooManyRequests
+	}
+	return status
+}
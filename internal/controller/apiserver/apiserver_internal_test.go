@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -11,7 +12,11 @@ import (
 	"time"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
-	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/health"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+	"github.com/AnatoliyBr/todo-app/internal/oidc"
+	"github.com/AnatoliyBr/todo-app/internal/ratelimit"
+	"github.com/AnatoliyBr/todo-app/internal/signing"
 	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
 	"github.com/AnatoliyBr/todo-app/internal/usecase"
 	"github.com/golang-jwt/jwt/v5"
@@ -20,11 +25,9 @@ import (
 )
 
 func TestServer_HandleHello(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 	rec := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/hello", nil)
 
@@ -33,12 +36,127 @@ func TestServer_HandleHello(t *testing.T) {
 	assert.NotNil(t, rec.Body)
 }
 
+func TestServer_HandleHealth(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	checks := health.NewChecker()
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t)).WithHealth(checks)
+
+	var dbErr error
+	checks.Register("db", func(ctx context.Context) error { return dbErr })
+
+	testCases := []struct {
+		name           string
+		path           string
+		prepare        func()
+		expectedCode   int
+		expectedReport *health.Report
+	}{
+		{
+			name:           "alive",
+			path:           "/healthz",
+			prepare:        func() {},
+			expectedCode:   http.StatusOK,
+			expectedReport: &health.Report{Status: health.StatusOK},
+		},
+		{
+			name:         "ready",
+			path:         "/readyz",
+			prepare:      func() {},
+			expectedCode: http.StatusOK,
+			expectedReport: &health.Report{
+				Status: health.StatusOK,
+				Checks: map[string]*health.Result{
+					"db": {Status: health.StatusOK},
+				},
+			},
+		},
+		{
+			name: "check failing",
+			path: "/readyz",
+			prepare: func() {
+				dbErr = errors.New("connection refused")
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedReport: &health.Report{
+				Status: health.StatusFailing,
+				Checks: map[string]*health.Result{
+					"db": {Status: health.StatusFailing, Error: "connection refused"},
+				},
+			},
+		},
+		{
+			name: "shutting down",
+			path: "/readyz",
+			prepare: func() {
+				dbErr = nil
+				checks.Shutdown()
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedReport: &health.Report{
+				Status: health.StatusFailing,
+				Checks: map[string]*health.Result{
+					"db":       {Status: health.StatusOK},
+					"shutdown": {Status: health.StatusFailing, Error: health.ErrShuttingDown.Error()},
+				},
+			},
+		},
+		{
+			name:           "alive while shutting down",
+			path:           "/healthz",
+			prepare:        func() {},
+			expectedCode:   http.StatusOK,
+			expectedReport: &health.Report{Status: health.StatusOK},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.prepare()
+
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			report := &health.Report{}
+			json.NewDecoder(rec.Body).Decode(report)
+			assert.Equal(t, tc.expectedReport, report)
+		})
+	}
+}
+
+func TestServer_StartServer(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	config := NewConfig()
+	config.BindAddr = "127.0.0.1:0"
+	config.ShutdownDelay = 10 * time.Millisecond
+	s := NewServer(config, uc, signing.TestKeySet(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.StartServer(ctx)
+	}()
+
+	cancel()
+	select {
+	case err := <-errs:
+		assert.NoError(t, err)
+	case <-time.After(config.ShutdownDelay + config.ShutdownTimeout):
+		t.Fatal("server did not shut down")
+	}
+	assert.False(t, s.health.Ready(context.Background()).OK())
+
+	config.BindAddr = "incorrect"
+	assert.Error(t, s.StartServer(context.Background()))
+}
+
 func TestServer_SetRequestID(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -51,23 +169,353 @@ func TestServer_SetRequestID(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
 }
-func TestServer_AuthenticateUser(t *testing.T) {
-	type tokenClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
+func TestServer_RateLimit(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	config := ratelimit.NewConfig()
+	config.Limits = map[string]ratelimit.Limit{
+		ratelimit.GroupPublic: {Requests: 1, Per: time.Minute},
+		"lists":               {Requests: 2, Per: time.Minute},
+	}
+	limiter, _ := ratelimit.NewLimiter(config, nil)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t)).WithRateLimiter(limiter)
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
+	other := entity.TestUser(t)
+	other.Email = "other@example.org"
+	s.uc.UsersCreate(other)
+	for _, user := range []*entity.User{u, other} {
+		l := entity.TestList(t)
+		l.UserID = user.UserID
+		s.uc.ListsCreate(l)
+	}
+	token, _ := s.issueToken(u, false)
+	otherToken, _ := s.issueToken(other, false)
+
+	testCases := []struct {
+		name               string
+		method             string
+		path               string
+		remoteAddr         string
+		token              string
+		expectedCode       int
+		expectedRemaining  string
+		expectedRetryAfter string
+	}{
+		{
+			name:              "public",
+			method:            http.MethodGet,
+			path:              "/.well-known/jwks.json",
+			remoteAddr:        "192.0.2.1:1234",
+			expectedCode:      http.StatusOK,
+			expectedRemaining: "0",
+		},
+		{
+			name:               "public limited",
+			method:             http.MethodGet,
+			path:               "/.well-known/jwks.json",
+			remoteAddr:         "192.0.2.1:5678",
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRemaining:  "0",
+			expectedRetryAfter: "60",
+		},
+		{
+			name:              "public other ip",
+			method:            http.MethodGet,
+			path:              "/.well-known/jwks.json",
+			remoteAddr:        "192.0.2.2:1234",
+			expectedCode:      http.StatusOK,
+			expectedRemaining: "0",
+		},
+		{
+			name:              "private",
+			method:            http.MethodGet,
+			path:              "/lists",
+			remoteAddr:        "192.0.2.1:1234",
+			token:             token,
+			expectedCode:      http.StatusOK,
+			expectedRemaining: "1",
+		},
+		{
+			name:              "private same user",
+			method:            http.MethodGet,
+			path:              "/lists",
+			remoteAddr:        "192.0.2.2:1234",
+			token:             token,
+			expectedCode:      http.StatusOK,
+			expectedRemaining: "0",
+		},
+		{
+			name:               "private limited",
+			method:             http.MethodGet,
+			path:               "/lists",
+			remoteAddr:         "192.0.2.1:1234",
+			token:              token,
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRemaining:  "0",
+			expectedRetryAfter: "30",
+		},
+		{
+			name:              "private other user",
+			method:            http.MethodGet,
+			path:              "/lists",
+			remoteAddr:        "192.0.2.1:1234",
+			token:             otherToken,
+			expectedCode:      http.StatusOK,
+			expectedRemaining: "1",
+		},
+		{
+			name:         "no limit",
+			method:       http.MethodGet,
+			path:         "/profile",
+			remoteAddr:   "192.0.2.1:1234",
+			token:        token,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			assert.Equal(t, tc.expectedRemaining, rec.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, tc.expectedRetryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestServer_RateLimitAuth(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	config := ratelimit.NewConfig()
+	config.Limits = map[string]ratelimit.Limit{
+		ratelimit.GroupAuth: {Requests: 2, Per: time.Minute},
+	}
+	limiter, _ := ratelimit.NewLimiter(config, nil)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t)).WithRateLimiter(limiter)
+
+	testCases := []struct {
+		name               string
+		remoteAddr         string
+		token              string
+		expectedCode       int
+		expectedRetryAfter string
+	}{
+		{
+			name:         "invalid token",
+			remoteAddr:   "192.0.2.1:1234",
+			token:        "invalid",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown api key",
+			remoteAddr:   "192.0.2.1:1234",
+			token:        entity.APIKeyPrefix + "unknown",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "limited",
+			remoteAddr:         "192.0.2.1:5678",
+			token:              entity.APIKeyPrefix + "other",
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRetryAfter: "30",
+		},
+		{
+			name:         "other ip",
+			remoteAddr:   "192.0.2.2:1234",
+			token:        entity.APIKeyPrefix + "other",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/lists", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			assert.Equal(t, tc.expectedRetryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestNewTrustedProxies(t *testing.T) {
+	testCases := []struct {
+		name    string
+		proxies []string
+		isValid bool
+	}{
+		{
+			name:    "none",
+			proxies: nil,
+			isValid: true,
+		},
+		{
+			name:    "cidrs",
+			proxies: []string{"10.0.0.0/8", "2001:db8::/32"},
+			isValid: true,
+		},
+		{
+			name:    "address without mask",
+			proxies: []string{"10.0.0.1"},
+			isValid: false,
+		},
+		{
+			name:    "invalid",
+			proxies: []string{"proxy"},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig()
+			config.TrustedProxies = tc.proxies
+			_, err := NewTrustedProxies(config)
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestServer_ClientIP(t *testing.T) {
+	config := NewConfig()
+	config.TrustedProxies = []string{"10.0.0.0/8"}
+	proxies, err := NewTrustedProxies(config)
+	assert.NoError(t, err)
+
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), testrepository.TestStore(t))
+	s := NewServer(config, uc, signing.TestKeySet(t)).WithTrustedProxies(proxies)
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		expected   string
+	}{
+		{
+			name:       "direct",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "forwarded by untrusted client",
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.1"}},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.1:1234",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "forwarded for",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "spoofed forwarded for",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.1, 198.51.100.1, 10.0.0.2"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "several forwarded for headers",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.1", "198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "invalid forwarded for",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"unknown"}},
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "real ip",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Real-Ip": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "ipv4 mapped proxy",
+			remoteAddr: "[::ffff:10.0.0.1]:1234",
+			header:     http.Header{"X-Real-Ip": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/lists", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header = tc.header
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+
+			assert.Equal(t, tc.expected, s.clientIP(req))
+		})
+	}
+}
+
+func TestServer_RateLimitTrustedProxy(t *testing.T) {
+	config := NewConfig()
+	config.TrustedProxies = []string{"10.0.0.0/8"}
+	proxies, _ := NewTrustedProxies(config)
+	rateLimitConfig := ratelimit.NewConfig()
+	rateLimitConfig.Limits = map[string]ratelimit.Limit{
+		ratelimit.GroupPublic: {Requests: 1, Per: time.Minute},
 	}
+	limiter, _ := ratelimit.NewLimiter(rateLimitConfig, nil)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), testrepository.TestStore(t))
+	s := NewServer(config, uc, signing.TestKeySet(t)).WithRateLimiter(limiter).WithTrustedProxies(proxies)
 
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
+	request := func(forwardedFor string) int {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/tokens", bytes.NewBufferString("{}"))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+
+		s.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, request("198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("198.51.100.1"))
+	assert.NotEqual(t, http.StatusTooManyRequests, request("198.51.100.2"))
+}
+
+func TestServer_AuthenticateUser(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 	u := entity.TestUser(t)
 	s.uc.UsersCreate(u)
 
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	key, _ := s.uc.APIKeysCreate(k)
+
 	testCases := []struct {
 		name         string
 		tokenString  func() string
+		method       string
 		expectedCode int
 	}{
 		{
@@ -76,11 +524,11 @@ func TestServer_AuthenticateUser(t *testing.T) {
 				claims := &tokenClaims{
 					UserID: u.UserID,
 					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{audienceToken},
 						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
 					},
 				}
-				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(s.config.SecretKey))
+				tokenString, _ := s.keys.Sign(claims)
 				return tokenString
 			},
 			expectedCode: http.StatusOK,
@@ -96,84 +544,2539 @@ func TestServer_AuthenticateUser(t *testing.T) {
 			name: "not authenticated",
 			tokenString: func() string {
 				claims := &tokenClaims{}
+				tokenString, _ := s.keys.Sign(claims)
+				return tokenString
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "expired token",
+			tokenString: func() string {
+				claims := &tokenClaims{
+					UserID: u.UserID,
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{audienceToken},
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute * 5)),
+					},
+				}
+				tokenString, _ := s.keys.Sign(claims)
+				return tokenString
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "stale token version",
+			tokenString: func() string {
+				claims := &tokenClaims{
+					UserID:       u.UserID,
+					TokenVersion: u.TokenVersion + 1,
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{audienceToken},
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+					},
+				}
+				tokenString, _ := s.keys.Sign(claims)
+				return tokenString
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "hmac token",
+			tokenString: func() string {
+				claims := &tokenClaims{
+					UserID: u.UserID,
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{audienceToken},
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+					},
+				}
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(s.config.SecretKey))
+				tokenString, _ := token.SignedString([]byte("Hj/XWYwIXa9JIXQmZzIHjoMuD/LQiA+omQjUhQx3QmHA/VcyJNFCn8btu3Vh3kGw"))
+				return tokenString
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "oidc state token",
+			tokenString: func() string {
+				claims := &oidcClaims{
+					State: "state",
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{audienceOIDCState},
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 10)),
+					},
+				}
+				tokenString, _ := s.keys.Sign(claims)
+				return tokenString
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "mfa pending token",
+			tokenString: func() string {
+				tokenString, _ := s.issueToken(u, true)
 				return tokenString
 			},
 			expectedCode: http.StatusUnauthorized,
 		},
 		{
-			name: "expired token",
-			tokenString: func() string {
-				claims := &tokenClaims{
-					UserID: u.UserID,
-					RegisteredClaims: jwt.RegisteredClaims{
-						ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute * 5)),
-					},
-				}
-				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(s.config.SecretKey))
-				return tokenString
-			},
-			expectedCode: http.StatusUnauthorized,
+			name: "api key",
+			tokenString: func() string {
+				return key
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "read-only api key",
+			tokenString: func() string {
+				return key
+			},
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "unknown api key",
+			tokenString: func() string {
+				return entity.APIKeyPrefix + "unknown"
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, "/", nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tc.tokenString()))
+
+			s.authenticateUser(handler).ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_RejectAPIKey(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	k.Scope = entity.ScopeWrite
+	key, _ := s.uc.APIKeysCreate(k)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{
+			name:         "profile",
+			method:       http.MethodGet,
+			path:         "/profile",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "delete account",
+			method:       http.MethodDelete,
+			path:         "/profile",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "edit timezone",
+			method:       http.MethodPut,
+			path:         "/profile/timezone",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "edit password",
+			method:       http.MethodPut,
+			path:         "/profile/password",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "edit email",
+			method:       http.MethodPut,
+			path:         "/profile/email",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "send verification",
+			method:       http.MethodPost,
+			path:         "/profile/verification",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "enroll mfa",
+			method:       http.MethodPost,
+			path:         "/profile/mfa",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "confirm mfa",
+			method:       http.MethodPost,
+			path:         "/profile/mfa/confirm",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "disable mfa",
+			method:       http.MethodDelete,
+			path:         "/profile/mfa",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "create api key",
+			method:       http.MethodPost,
+			path:         "/api-keys",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleJWKS(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	oldKey, _ := signing.GenerateKey()
+	newKey, _ := signing.GenerateKey()
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	old := NewServer(NewConfig(), uc, signing.NewKeySet(oldKey))
+	tokenString, _ := old.issueToken(u, false)
+
+	s := NewServer(NewConfig(), uc, signing.NewKeySet(newKey, oldKey))
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	res := map[string][]*signing.JWK{}
+	json.NewDecoder(rec.Body).Decode(&res)
+	assert.Len(t, res["keys"], 2)
+	assert.Equal(t, newKey.ID, res["keys"][0].Kid)
+	assert.Equal(t, oldKey.ID, res["keys"][1].Kid)
+
+	// tokens signed before the rotation stay valid
+	parsed, err := s.parseToken(tokenString, false)
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, parsed.UserID)
+}
+
+func TestServer_HandleUsersCreate(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"email":    "user@example.org",
+				"password": "password",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid params",
+			payload: map[string]string{
+				"email": "invalid",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid timezone",
+			payload: map[string]string{
+				"email":    "user2@example.org",
+				"password": "password",
+				"timezone": "invalid",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/users", b)
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTokensCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"email":    u.Email,
+				"password": u.Password,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid email",
+			payload: map[string]string{
+				"email":    "invalid",
+				"password": u.Password,
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "invalid password",
+			payload: map[string]string{
+				"email":    u.Email,
+				"password": "invalid",
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/tokens", b)
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTokensCreate_Lockout(t *testing.T) {
+	u := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.LoginFreeAttempts = 1
+	uc := usecase.NewAppUseCase(config, store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(map[string]string{
+			"email":    u.Email,
+			"password": password,
+		})
+		req, _ := http.NewRequest(http.MethodPost, "/tokens", b)
+		req.RemoteAddr = "10.0.0.1:54321"
+
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("invalid").Code)
+
+	rec := login(u.Password)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
+
+func TestServer_HandleUserProfile(t *testing.T) {
+	u1 := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u1)
+	u1.Sanitize()
+	u1.EncryptedPassword = ""
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/profile", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u1))
+
+	s.handleUserProfile().ServeHTTP(rec, req)
+
+	u2 := &entity.User{}
+	json.NewDecoder(rec.Body).Decode(u2)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, u1, u2)
+}
+
+func TestServer_HandleUserTimezoneEdit(t *testing.T) {
+	u := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"timezone": "Europe/Moscow",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid timezone",
+			payload: map[string]string{
+				"timezone": "Europe/Gotham",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "empty timezone",
+			payload:      map[string]string{},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, "/profile/timezone", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleUserTimezoneEdit().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	assert.Equal(t, "Europe/Moscow", u.Timezone)
+}
+
+func TestSerer_HandleListsCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"list_title": l.ListTitle,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "cyrillic title",
+			payload: map[string]string{
+				"list_title": "Рабочие задачи",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid title",
+			payload: map[string]string{
+				"list_title": "inv@li_d title *",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/lists", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleListsCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleListsGetByUser(t *testing.T) {
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+	s.uc.ListsCreate(l1)
+	s.uc.ListsCreate(l2)
+
+	testCases := []struct {
+		name          string
+		path          string
+		prepare       func()
+		expectedCode  int
+		expectedLists int
+	}{
+		{
+			name:          "active",
+			path:          "/lists",
+			prepare:       func() {},
+			expectedCode:  http.StatusOK,
+			expectedLists: 2,
+		},
+		{
+			name:          "nothing archived",
+			path:          "/lists?archived=true",
+			prepare:       func() {},
+			expectedCode:  http.StatusOK,
+			expectedLists: 0,
+		},
+		{
+			name: "everything archived",
+			path: "/lists",
+			prepare: func() {
+				s.uc.ListsArchive(l1)
+				s.uc.ListsArchive(l2)
+			},
+			expectedCode:  http.StatusOK,
+			expectedLists: 0,
+		},
+		{
+			name:          "archived",
+			path:          "/lists?archived=true",
+			prepare:       func() {},
+			expectedCode:  http.StatusOK,
+			expectedLists: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.prepare()
+
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleListsGetByUser().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			var lists []*entity.List
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&lists))
+			assert.NotNil(t, lists)
+			assert.Len(t, lists, tc.expectedLists)
+		})
+	}
+}
+
+func TestServer_HandleListsGetByID(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+
+	assert.NotNil(t, l.ListID)
+
+	testCases := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			id:           "1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			id:           "2",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid",
+			id:           "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/lists/%s", tc.id), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+
+			s.handleListsGetByID().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleListsEdit(t *testing.T) {
+	u := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	other := entity.TestList(t)
+	other.ListTitle = "OTHER TITLE"
+	other.UserID = u.UserID
+	s.uc.ListsCreate(other)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "2",
+			payload: map[string]string{
+				"list_title": "NEW TITLE 2",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "3",
+			payload: map[string]string{
+				"list_title": "TEST TITLE 2",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "invalid id",
+			id:   "invalid",
+			payload: map[string]string{
+				"list_title": "TEST TITLE 2",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid title",
+			id:   "2",
+			payload: map[string]string{
+				"list_title": "inv@li_d title *",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "own title in another case",
+			id:   "2",
+			payload: map[string]string{
+				"list_title": "Test Title 1",
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "existing title",
+			id:   "2",
+			payload: map[string]string{
+				"list_title": "OTHER TITLE",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "existing title in another case",
+			id:   "2",
+			payload: map[string]string{
+				"list_title": "Other Title",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := entity.TestList(t)
+			l.UserID = u.UserID
+			s.uc.ListsCreate(l)
+			assert.NotNil(t, l.ListID)
+
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/lists/%s", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+
+			s.handleListsEdit().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			s.uc.ListsDelete(l)
+		})
+	}
+}
+
+func TestServer_HandleTasksCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "1",
+			payload: map[string]string{
+				"task_title": "test task 1",
+				"deadline":   "2026-11-01T12:00:00+03:00",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "all day",
+			id:   "1",
+			payload: map[string]string{
+				"task_title": "test task 2",
+				"due_date":   "2026-11-01",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "deadline without offset",
+			id:   "1",
+			payload: map[string]string{
+				"task_title": "test task 3",
+				"deadline":   "2026-11-01T12:00:00",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "list not found",
+			id:   "2",
+			payload: map[string]string{
+				"task_title": "test task 3",
+				"due_date":   "2026-11-01",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "without deadline",
+			id:   "1",
+			payload: map[string]string{
+				"task_title": "test task 3",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/lists/%s/tasks", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+
+			s.handleTasksCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTasksGetByList(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u.Timezone = "Europe/Moscow"
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/lists/1/tasks", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+	req = mux.SetURLVars(req, map[string]string{"listID": "1"})
+
+	s.handleTasksGetByList().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	tasks := []map[string]interface{}{}
+	json.NewDecoder(rec.Body).Decode(&tasks)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "2026-11-01T15:00:00+03:00", tasks[0]["deadline"])
+}
+
+func TestServer_HandleTasksGetByID(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			id:           "1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			id:           "2",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid",
+			id:           "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", tc.id), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleTasksGetByID().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTasksEdit(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "1",
+			payload: map[string]interface{}{
+				"task_title": "test task 2",
+				"due_date":   "2026-11-02",
+				"done":       true,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "2",
+			payload: map[string]interface{}{
+				"task_title": "test task 2",
+				"due_date":   "2026-11-02",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "invalid title",
+			id:   "1",
+			payload: map[string]interface{}{
+				"task_title": "",
+				"due_date":   "2026-11-02",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleTasksEdit().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTasksDelete(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			id:           "1",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "already deleted",
+			id:           "1",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", tc.id), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleTasksDelete().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleItemsCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "1",
+			payload: map[string]string{
+				"item_title": "test item 1",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "task not found",
+			id:   "2",
+			payload: map[string]string{
+				"item_title": "test item 1",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "empty title",
+			id:           "1",
+			payload:      map[string]string{},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%s/items", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleItemsCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleItemsReorder(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	i2 := entity.TestItem(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+	i1.TaskID = task.TaskID
+	i2.TaskID = task.TaskID
+	s.uc.ItemsCreate(i1)
+	s.uc.ItemsCreate(i2)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string][]int{
+				"item_ids": {i2.ItemID, i1.ItemID},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "missing item",
+			payload: map[string][]int{
+				"item_ids": {i1.ItemID},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, "/tasks/1/items/order", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": "1"})
+
+			s.handleItemsReorder().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleItemsToggle(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i := entity.TestItem(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+	i.TaskID = task.TaskID
+	s.uc.ItemsCreate(i)
+
+	testCases := []struct {
+		name         string
+		itemID       string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			itemID:       "1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			itemID:       "2",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/1/items/%s/toggle", tc.itemID), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": "1", "itemID": tc.itemID})
+
+			s.handleItemsToggle().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	assert.True(t, i.Done)
+}
+
+func TestServer_HandleDependenciesCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "2",
+			payload: map[string]int{
+				"blocker_id": 1,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			id:           "2",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "task not found",
+			id:   "3",
+			payload: map[string]int{
+				"blocker_id": 1,
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "blocker not found",
+			id:   "2",
+			payload: map[string]int{
+				"blocker_id": 3,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "cycle",
+			id:   "1",
+			payload: map[string]int{
+				"blocker_id": 2,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%s/dependencies", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleDependenciesCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleDependenciesDelete(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+	s.uc.DependenciesCreate(&entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID})
+
+	testCases := []struct {
+		name         string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "already deleted",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, "/tasks/2/dependencies/1", nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": "2", "blockerID": "1"})
+
+			s.handleDependenciesDelete().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTasksMove(t *testing.T) {
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+	s.uc.ListsCreate(l1)
+	s.uc.ListsCreate(l2)
+	t1.ListID = l1.ListID
+	t2.ListID = l1.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]int{
+				"before_id": 1,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "to another list",
+			payload: map[string]int{
+				"list_id": 2,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "list not found",
+			payload: map[string]int{
+				"list_id": 3,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "anchor not found",
+			payload: map[string]int{
+				"after_id": 3,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/tasks/2/move", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": "2"})
+
+			s.handleTasksMove().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleLabelsCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestLabel(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]string{
+				"label_title": l.LabelTitle,
+				"color":       l.Color,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid color",
+			payload: map[string]string{
+				"label_title": "later",
+				"color":       "red",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/labels", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleLabelsCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTaskLabelsAttach(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	lb := entity.TestLabel(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+	lb.UserID = u.UserID
+	s.uc.LabelsCreate(lb)
+
+	testCases := []struct {
+		name         string
+		taskID       string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:   "valid",
+			taskID: "1",
+			payload: map[string]int{
+				"label_id": lb.LabelID,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid payload",
+			taskID:       "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "task not found",
+			taskID: "2",
+			payload: map[string]int{
+				"label_id": lb.LabelID,
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "label not found",
+			taskID: "1",
+			payload: map[string]int{
+				"label_id": 2,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/tasks/"+tc.taskID+"/labels", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.taskID})
+
+			s.handleTaskLabelsAttach().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleTasksGetByList_Labels(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	lb1 := entity.TestLabel(t)
+	lb2 := entity.TestLabel(t)
+	lb2.LabelTitle = "later"
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+	lb1.UserID = u.UserID
+	lb2.UserID = u.UserID
+	s.uc.LabelsCreate(lb1)
+	s.uc.LabelsCreate(lb2)
+	s.uc.LabelsAttach(t1.TaskID, lb1.LabelID)
+	s.uc.LabelsAttach(t1.TaskID, lb2.LabelID)
+	s.uc.LabelsAttach(t2.TaskID, lb2.LabelID)
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedLen  int
+	}{
+		{
+			name:         "no labels",
+			query:        "",
+			expectedCode: http.StatusOK,
+			expectedLen:  2,
+		},
+		{
+			name:         "one label",
+			query:        "?label=1",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "any label",
+			query:        "?label=1,2",
+			expectedCode: http.StatusOK,
+			expectedLen:  2,
+		},
+		{
+			name:         "all labels",
+			query:        "?label=1&label=2&label_match=all",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "invalid label",
+			query:        "?label=urgent",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid label match",
+			query:        "?label=1&label_match=some",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/lists/1/tasks"+tc.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": "1"})
+
+			s.handleTasksGetByList().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				tasks := []map[string]interface{}{}
+				json.NewDecoder(rec.Body).Decode(&tasks)
+				assert.Len(t, tasks, tc.expectedLen)
+			}
+		})
+	}
+}
+
+func TestServer_HandleTasksGetByList_Sort(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.Priority = entity.PriorityUrgent
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+
+	testCases := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedIDs   []float64
+		firstPriority string
+	}{
+		{
+			name:          "position",
+			query:         "",
+			expectedCode:  http.StatusOK,
+			expectedIDs:   []float64{1, 2},
+			firstPriority: "none",
+		},
+		{
+			name:          "priority",
+			query:         "?sort=priority",
+			expectedCode:  http.StatusOK,
+			expectedIDs:   []float64{2, 1},
+			firstPriority: "urgent",
+		},
+		{
+			name:         "invalid sort",
+			query:        "?sort=title",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/lists/1/tasks"+tc.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": "1"})
+
+			s.handleTasksGetByList().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				tasks := []map[string]interface{}{}
+				json.NewDecoder(rec.Body).Decode(&tasks)
+				ids := []float64{}
+				for _, task := range tasks {
+					ids = append(ids, task["task_id"].(float64))
+				}
+				assert.Equal(t, tc.expectedIDs, ids)
+				assert.Equal(t, tc.firstPriority, tasks[0]["priority"])
+			}
+		})
+	}
+}
+
+func TestServer_HandleViewsCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			payload: map[string]interface{}{
+				"view_title": "overdue and high priority",
+				"filter": map[string]interface{}{
+					"overdue":      true,
+					"min_priority": "high",
+				},
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown priority",
+			payload: map[string]interface{}{
+				"view_title": "asap",
+				"filter": map[string]interface{}{
+					"min_priority": "asap",
+				},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "without filter",
+			payload: map[string]interface{}{
+				"view_title": "everything",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid filter",
+			payload: map[string]interface{}{
+				"view_title": "backwards",
+				"filter": map[string]interface{}{
+					"due_from_days": 7,
+					"due_to_days":   1,
+				},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/views", b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleViewsCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleViewsGetTasks(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.Done = true
+	t2.Priority = entity.PriorityHigh
+	v := entity.TestView(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.uc.TasksCreate(t1)
+	s.uc.TasksCreate(t2)
+	v.UserID = u.UserID
+	s.uc.ViewsCreate(v)
+
+	testCases := []struct {
+		name         string
+		view         string
+		expectedCode int
+		expectedLen  int
+	}{
+		{
+			name:         "saved",
+			view:         "1",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "built-in",
+			view:         "completed",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "saved not found",
+			view:         "2",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "built-in not found",
+			view:         "someday",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/views/%s/tasks", tc.view), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"view": tc.view})
+
+			s.handleViewsGetTasks().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				tasks := []map[string]interface{}{}
+				json.NewDecoder(rec.Body).Decode(&tasks)
+				assert.Len(t, tasks, tc.expectedLen)
+			}
+		})
+	}
+}
+
+func TestServer_HandleListsCreateFromTemplate(t *testing.T) {
+	u := entity.TestUser(t)
+	tpl := entity.TestTemplate(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	tpl.UserID = u.UserID
+	store.Template().Create(tpl)
+
+	testCases := []struct {
+		name         string
+		query        string
+		title        string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			query:        "?from_template=1&start=2026-11-01",
+			title:        "release 1",
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "without start",
+			query:        "?from_template=1",
+			title:        "release 2",
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid template",
+			query:        "?from_template=first",
+			title:        "release 3",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid start",
+			query:        "?from_template=1&start=01.11.2026",
+			title:        "release 3",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "template not found",
+			query:        "?from_template=2",
+			title:        "release 3",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "existing title",
+			query:        "?from_template=1",
+			title:        "release 1",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(map[string]string{"list_title": tc.title})
+			req, _ := http.NewRequest(http.MethodPost, "/lists"+tc.query, b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleListsCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	tasks, err := s.uc.TasksFindByList(1)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "2026-11-01T10:00:00Z", tasks[0].Deadline.Format(time.RFC3339))
+	assert.Equal(t, "2026-11-03", tasks[1].DueDate.Format("2006-01-02"))
+}
+
+func TestServer_HandleTemplatesCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			id:   "1",
+			payload: map[string]string{
+				"template_title": "weekly",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "list not found",
+			id:   "2",
+			payload: map[string]string{
+				"template_title": "monthly",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "empty title",
+			id:   "1",
+			payload: map[string]string{
+				"template_title": "",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/lists/%s/template", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+
+			s.handleTemplatesCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleListsArchive(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			id:           "1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "already archived",
+			id:           "1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown list",
+			id:           "2",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/lists/%s/archive", tc.id), nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+
+			s.handleListsArchive().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	t.Run("read-only tasks", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+		req = mux.SetURLVars(req, map[string]string{"taskID": "1"})
+
+		s.handleTasksDelete().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	listTestCases := []struct {
+		name         string
+		archived     string
+		expectedCode int
+	}{
+		{
+			name:         "archived",
+			archived:     "true",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid",
+			archived:     "maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range listTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/lists?archived="+tc.archived, nil)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+
+			s.handleListsGetByUser().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/lists/1/unarchive", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+	req = mux.SetURLVars(req, map[string]string{"listID": "1"})
+
+	s.handleListsUnarchive().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "archived_at")
+}
+
+func TestServer_HandleRemindersCreate(t *testing.T) {
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	s.uc.UsersCreate(u)
+	l.UserID = u.UserID
+	s.uc.ListsCreate(l)
+	task.ListID = l.ListID
+	s.uc.TasksCreate(task)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "offset",
+			id:   "1",
+			payload: map[string]interface{}{
+				"offset": 30,
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "remind at",
+			id:   "1",
+			payload: map[string]interface{}{
+				"remind_at": "2026-10-31T09:00:00+03:00",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid payload",
+			id:           "1",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "task not found",
+			id:   "2",
+			payload: map[string]interface{}{
+				"offset": 30,
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "without time",
+			id:           "1",
+			payload:      map[string]interface{}{},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%s/reminders", tc.id), b)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req = mux.SetURLVars(req, map[string]string{"taskID": tc.id})
+
+			s.handleRemindersCreate().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandlePasswordResets(t *testing.T) {
+	store := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store).WithMailer(mailer)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		path         func() string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "unknown email",
+			path:         func() string { return "/password-resets" },
+			payload:      map[string]string{"email": "unknown@example.org"},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "known email",
+			path:         func() string { return "/password-resets" },
+			payload:      map[string]string{"email": u.Email},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "invalid payload",
+			path:         func() string { return "/password-resets" },
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "short password",
+			path: func() string {
+				return "/password-resets/" + sender.LastToken(t)
+			},
+			payload:      map[string]string{"password": "short"},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			path: func() string {
+				return "/password-resets/" + sender.LastToken(t)
+			},
+			payload:      map[string]string{"password": "new password"},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "used token",
+			path: func() string {
+				return "/password-resets/" + sender.LastToken(t)
+			},
+			payload:      map[string]string{"password": "new password"},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, tc.path(), b)
+
+			s.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleEmailVerifications(t *testing.T) {
+	store := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store).WithMailer(mailer)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+
+	rec := httptest.NewRecorder()
+	b := &bytes.Buffer{}
+	json.NewEncoder(b).Encode(map[string]string{
+		"email":    "user@example.org",
+		"password": "password",
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/users", b)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Len(t, sender.Messages, 1)
+
+	u, _ := s.uc.UsersFindByEmail("user@example.org")
+
+	testCases := []struct {
+		name         string
+		send         bool
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "resend too soon",
+			send:         true,
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown token",
+			payload:      map[string]string{"token": "unknown"},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "valid",
+			payload:      map[string]string{"token": sender.LastToken(t)},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "resend verified",
+			send:         true,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tc.send {
+				u, _ = s.uc.UsersFindByID(u.UserID)
+				req, _ := http.NewRequest(http.MethodPost, "/profile/verification", nil)
+				req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+				s.handleEmailVerificationsSend().ServeHTTP(rec, req)
+			} else {
+				b := &bytes.Buffer{}
+				json.NewEncoder(b).Encode(tc.payload)
+				req, _ := http.NewRequest(http.MethodPost, "/users/verify", b)
+				s.ServeHTTP(rec, req)
+			}
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestServer_HandleUserPasswordEdit(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
+	oldToken, _ := s.issueToken(u, false)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "incorrect password",
+			payload: map[string]string{
+				"current_password": "wrong",
+				"password":         "new password",
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "short password",
+			payload: map[string]string{
+				"current_password": "password",
+				"password":         "short",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			payload: map[string]string{
+				"current_password": "password",
+				"password":         "new password",
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, "/profile/password", b)
+			current, _ := s.uc.UsersFindByID(u.UserID)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, current))
+
+			s.handleUserPasswordEdit().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	_, err := s.parseToken(oldToken, false)
+	assert.Error(t, err)
+}
+
+func TestServer_HandleUserEmailEdit(t *testing.T) {
+	store := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store).WithMailer(mailer)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u1 := entity.TestUser(t)
+	s.uc.UsersCreate(u1)
+	u2 := entity.TestUser(t)
+	u2.Email = "other@example.org"
+	s.uc.UsersCreate(u2)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name: "incorrect password",
+			payload: map[string]string{
+				"password": "wrong",
+				"email":    "new@example.org",
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "taken email",
+			payload: map[string]string{
+				"password": "password",
+				"email":    u2.Email,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid email",
+			payload: map[string]string{
+				"password": "password",
+				"email":    "invalid",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			payload: map[string]string{
+				"password": "password",
+				"email":    "new@example.org",
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPut, "/profile/email", b)
+			current, _ := s.uc.UsersFindByID(u1.UserID)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, current))
+
+			s.handleUserEmailEdit().ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+
+	assert.Len(t, sender.Messages, 1)
+	assert.NoError(t, s.uc.EmailVerificationsConfirm(sender.LastToken(t)))
+}
+
+func TestServer_HandleUsersDelete(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
+
+	testCases := []struct {
+		name         string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "incorrect password",
+			payload:      map[string]string{"password": "wrong"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "valid",
+			payload:      map[string]string{"password": "password"},
+			expectedCode: http.StatusNoContent,
 		},
 	}
 
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
-
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			rec := httptest.NewRecorder()
-			req, _ := http.NewRequest(http.MethodGet, "/", nil)
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tc.tokenString()))
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodDelete, "/profile", b)
+			ctx := context.WithValue(req.Context(), ctxKeyUser, u)
 
-			s.authenticateUser(handler).ServeHTTP(rec, req)
+			s.handleUsersDelete().ServeHTTP(rec, req.WithContext(ctx))
 			assert.Equal(t, tc.expectedCode, rec.Code)
 		})
 	}
+
+	_, err := s.uc.UsersFindByID(u.UserID)
+	assert.Error(t, err)
 }
-func TestServer_HandleUsersCreate(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
+
+func TestServer_HandleAdminUsers(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	admin := entity.TestUser(t)
+	admin.Role = entity.RoleAdmin
+	s.uc.UsersCreate(admin)
+	u := entity.TestUser(t)
+	u.Email = "other@example.org"
+	s.uc.UsersCreate(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = admin.UserID
+	adminKey, _ := s.uc.APIKeysCreate(k)
+	adminToken, _ := s.issueToken(admin, false)
+	userToken, _ := s.issueToken(u, false)
 
 	testCases := []struct {
 		name         string
-		payload      interface{}
+		method       string
+		path         string
+		token        func() string
 		expectedCode int
 	}{
 		{
-			name: "valid",
-			payload: map[string]string{
-				"email":    "user@example.org",
-				"password": "password",
-			},
-			expectedCode: http.StatusCreated,
+			name:         "not admin",
+			method:       http.MethodGet,
+			path:         "/admin/users",
+			token:        func() string { return userToken },
+			expectedCode: http.StatusForbidden,
 		},
 		{
-			name:         "invalid payload",
-			payload:      "invalid",
-			expectedCode: http.StatusBadRequest,
+			name:         "admin api key",
+			method:       http.MethodGet,
+			path:         "/admin/users",
+			token:        func() string { return adminKey },
+			expectedCode: http.StatusForbidden,
 		},
 		{
-			name: "invalid params",
-			payload: map[string]string{
-				"email": "invalid",
-			},
+			name:         "list users",
+			method:       http.MethodGet,
+			path:         "/admin/users",
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "show user",
+			method:       http.MethodGet,
+			path:         fmt.Sprintf("/admin/users/%d", u.UserID),
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown user",
+			method:       http.MethodGet,
+			path:         "/admin/users/100",
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "disable self",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/admin/users/%d/disable", admin.UserID),
+			token:        func() string { return adminToken },
 			expectedCode: http.StatusUnprocessableEntity,
 		},
+		{
+			name:         "disable",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/admin/users/%d/disable", u.UserID),
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "disabled user",
+			method:       http.MethodGet,
+			path:         "/profile",
+			token:        func() string { tokenString, _ := s.issueToken(u, false); return tokenString },
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "enable",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/admin/users/%d/enable", u.UserID),
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "enabled user",
+			method:       http.MethodGet,
+			path:         "/profile",
+			token:        func() string { tokenString, _ := s.issueToken(u, false); return tokenString },
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "logout",
+			method:       http.MethodPost,
+			path:         fmt.Sprintf("/admin/users/%d/logout", u.UserID),
+			token:        func() string { return adminToken },
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "logged out user",
+			method:       http.MethodGet,
+			path:         "/profile",
+			token:        func() string { return userToken },
+			expectedCode: http.StatusUnauthorized,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			rec := httptest.NewRecorder()
-			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.payload)
-			req, _ := http.NewRequest(http.MethodPost, "/users", b)
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token())
 
 			s.ServeHTTP(rec, req)
 			assert.Equal(t, tc.expectedCode, rec.Code)
@@ -181,49 +3084,81 @@ func TestServer_HandleUsersCreate(t *testing.T) {
 	}
 }
 
-func TestServer_HandleTokensCreate(t *testing.T) {
+func TestServer_HandleTokensMFA(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store).WithMFAKey(entity.TestMFAKey(t))
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 	u := entity.TestUser(t)
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
 	s.uc.UsersCreate(u)
 
+	secret, _, _ := s.uc.MFAEnroll(u)
+	step := entity.TOTPStep(time.Now())
+	code, _ := entity.TOTPCode(secret, step)
+	recoveryCodes, _ := s.uc.MFAConfirm(u, code)
+
+	rec := httptest.NewRecorder()
+	b := &bytes.Buffer{}
+	json.NewEncoder(b).Encode(map[string]string{
+		"email":    u.Email,
+		"password": u.Password,
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/tokens", b)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Result().Cookies())
+
+	res := struct {
+		MFARequired bool   `json:"mfa_required"`
+		MFAToken    string `json:"mfa_token"`
+	}{}
+	json.NewDecoder(rec.Body).Decode(&res)
+	assert.True(t, res.MFARequired)
+
+	sessionToken, _ := s.issueToken(u, false)
+	next, _ := entity.TOTPCode(secret, step+1)
+
 	testCases := []struct {
 		name         string
 		payload      interface{}
 		expectedCode int
 	}{
-		{
-			name: "valid",
-			payload: map[string]string{
-				"email":    u.Email,
-				"password": u.Password,
-			},
-			expectedCode: http.StatusOK,
-		},
 		{
 			name:         "invalid payload",
-			payload:      "",
+			payload:      "invalid",
 			expectedCode: http.StatusBadRequest,
 		},
 		{
-			name: "invalid email",
+			name: "session token",
 			payload: map[string]string{
-				"email":    "invalid",
-				"password": u.Password,
+				"mfa_token": sessionToken,
+				"code":      next,
 			},
 			expectedCode: http.StatusUnauthorized,
 		},
 		{
-			name: "invalid password",
+			name: "used code",
 			payload: map[string]string{
-				"email":    u.Email,
-				"password": "invalid",
+				"mfa_token": res.MFAToken,
+				"code":      code,
 			},
 			expectedCode: http.StatusUnauthorized,
 		},
+		{
+			name: "valid",
+			payload: map[string]string{
+				"mfa_token": res.MFAToken,
+				"code":      next,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "recovery code",
+			payload: map[string]string{
+				"mfa_token": res.MFAToken,
+				"code":      recoveryCodes[0],
+			},
+			expectedCode: http.StatusOK,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -231,219 +3166,271 @@ func TestServer_HandleTokensCreate(t *testing.T) {
 			rec := httptest.NewRecorder()
 			b := &bytes.Buffer{}
 			json.NewEncoder(b).Encode(tc.payload)
-			req, _ := http.NewRequest(http.MethodPost, "/tokens", b)
+			req, _ := http.NewRequest(http.MethodPost, "/tokens/mfa", b)
 
 			s.ServeHTTP(rec, req)
 			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				assert.NotEmpty(t, rec.Result().Cookies())
+			}
 		})
 	}
 }
 
-func TestServer_HandleUserProfile(t *testing.T) {
-	u1 := entity.TestUser(t)
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
-	s.uc.UsersCreate(u1)
-	u1.Sanitize()
-	u1.EncryptedPassword = ""
+func TestServer_HandleTokensMFA_Lockout(t *testing.T) {
+	store := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.MFAFreeAttempts = 2
+	uc := usecase.NewAppUseCase(config, store).WithMFAKey(entity.TestMFAKey(t))
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+	u := entity.TestUser(t)
+	s.uc.UsersCreate(u)
 
-	rec := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodGet, "/profile", nil)
-	req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u1))
+	secret, _, _ := s.uc.MFAEnroll(u)
+	step := entity.TOTPStep(time.Now())
+	code, _ := entity.TOTPCode(secret, step)
+	s.uc.MFAConfirm(u, code)
 
-	s.handleUserProfile().ServeHTTP(rec, req)
+	next, _ := entity.TOTPCode(secret, step+1)
+	wrong, _ := entity.TOTPCode(secret, step-5)
+	mfaToken, _ := s.issueToken(u, true)
 
-	u2 := &entity.User{}
-	json.NewDecoder(rec.Body).Decode(u2)
+	verify := func(mfaToken, code string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(map[string]string{
+			"mfa_token": mfaToken,
+			"code":      code,
+		})
+		req, _ := http.NewRequest(http.MethodPost, "/tokens/mfa", b)
+		req.RemoteAddr = "10.0.0.1:54321"
 
-	assert.Equal(t, http.StatusOK, rec.Code)
-	assert.Equal(t, u1, u2)
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, verify(mfaToken, wrong).Code)
+	assert.Equal(t, http.StatusUnauthorized, verify(mfaToken, wrong).Code)
+
+	// the pending token is revoked after the failures
+	rec := verify(mfaToken, next)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, rec.Result().Cookies())
+
+	// and a new one is locked out for a while
+	mfaToken, _ = s.issueToken(u, true)
+	rec = verify(mfaToken, next)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
 }
 
-func TestSerer_HandleListsCreate(t *testing.T) {
-	u := entity.TestUser(t)
-	l := entity.TestList(t)
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
-	s.uc.UsersCreate(u)
+func TestServer_HandleOIDC(t *testing.T) {
+	m := oidc.TestServer(t)
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t)).WithOIDC(oidc.NewProvider(m.Config("http://localhost/oidc/callback")))
+
+	login := func(t *testing.T) (string, *http.Cookie) {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/oidc/login", nil)
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusFound, rec.Code)
+
+		callbackURL, err := m.Authorize(rec.Header().Get("Location"))
+		assert.NoError(t, err)
+		return callbackURL, rec.Result().Cookies()[0]
+	}
 
 	testCases := []struct {
-		name         string
-		payload      interface{}
-		expectedCode int
+		name          string
+		emailVerified bool
+		callbackURL   func(string) string
+		withCookie    bool
+		noAudience    bool
+		expectedCode  int
 	}{
 		{
-			name: "valid",
-			payload: map[string]string{
-				"list_title": l.ListTitle,
-			},
-			expectedCode: http.StatusCreated,
+			name:          "valid",
+			emailVerified: true,
+			callbackURL:   func(u string) string { return u },
+			withCookie:    true,
+			expectedCode:  http.StatusOK,
 		},
 		{
-			name:         "invalid payload",
-			payload:      "invalid",
-			expectedCode: http.StatusBadRequest,
+			name:          "without cookie",
+			emailVerified: true,
+			callbackURL:   func(u string) string { return u },
+			withCookie:    false,
+			expectedCode:  http.StatusBadRequest,
 		},
 		{
-			name: "invalid title",
-			payload: map[string]string{
-				"list_title": "inv@li_d title *",
-			},
-			expectedCode: http.StatusUnprocessableEntity,
+			name:          "cookie without audience",
+			emailVerified: true,
+			callbackURL:   func(u string) string { return u },
+			withCookie:    true,
+			noAudience:    true,
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "wrong state",
+			emailVerified: true,
+			callbackURL:   func(u string) string { return u + "x" },
+			withCookie:    true,
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "provider error",
+			emailVerified: true,
+			callbackURL:   func(string) string { return "/oidc/callback?error=access_denied" },
+			withCookie:    true,
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "unverified email",
+			emailVerified: false,
+			callbackURL:   func(u string) string { return u },
+			withCookie:    true,
+			expectedCode:  http.StatusForbidden,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			m.EmailVerified = tc.emailVerified
+			callbackURL, cookie := login(t)
+
 			rec := httptest.NewRecorder()
-			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.payload)
-			req, _ := http.NewRequest(http.MethodPost, "/lists", b)
-			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
+			req, _ := http.NewRequest(http.MethodGet, tc.callbackURL(callbackURL), nil)
+			if tc.noAudience {
+				claims := &oidcClaims{}
+				jwt.ParseWithClaims(cookie.Value, claims, s.keys.Keyfunc)
+				claims.Audience = nil
+				cookie.Value, _ = s.keys.Sign(claims)
+			}
+			if tc.withCookie {
+				req.AddCookie(cookie)
+			}
 
-			s.handleListsCreate().ServeHTTP(rec, req)
+			s.ServeHTTP(rec, req)
 			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				assert.Equal(t, "token", rec.Result().Cookies()[1].Name)
+			}
 		})
 	}
+
+	u, err := s.uc.UsersFindByEmail(m.Email)
+	assert.NoError(t, err)
+	assert.True(t, u.Verified())
 }
 
-func TestServer_HandleListsGetByUser(t *testing.T) {
+func TestServer_HandleOIDC_Disabled(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/oidc/login", nil)
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_HandleMFAEnroll(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store).WithMFAKey(entity.TestMFAKey(t))
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 	u := entity.TestUser(t)
-	l1 := entity.TestList(t)
-	l2 := entity.TestList(t)
-	l2.ListTitle = "TEST TITLE 2"
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
 	s.uc.UsersCreate(u)
-	l1.UserID = u.UserID
-	l2.UserID = u.UserID
-	s.uc.ListsCreate(l1)
-	s.uc.ListsCreate(l2)
 
 	rec := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodGet, "/lists", nil)
+	req, _ := http.NewRequest(http.MethodPost, "/profile/mfa", nil)
 	req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
-
-	s.handleListsGetByUser().ServeHTTP(rec, req)
+	s.handleMFAEnroll().ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusOK, rec.Code)
-}
 
-func TestServer_HandleListsGetByID(t *testing.T) {
-	u := entity.TestUser(t)
-	l := entity.TestList(t)
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
-	s.uc.UsersCreate(u)
-	l.UserID = u.UserID
-	s.uc.ListsCreate(l)
+	res := struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}{}
+	json.NewDecoder(rec.Body).Decode(&res)
+	assert.Contains(t, res.OTPAuthURI, res.Secret)
 
-	assert.NotNil(t, l.ListID)
+	code, _ := entity.TOTPCode(res.Secret, entity.TOTPStep(time.Now()))
 
 	testCases := []struct {
 		name         string
-		id           string
+		payload      interface{}
 		expectedCode int
 	}{
 		{
-			name:         "valid",
-			id:           "1",
-			expectedCode: http.StatusOK,
+			name:         "invalid payload",
+			payload:      "invalid",
+			expectedCode: http.StatusBadRequest,
 		},
 		{
-			name:         "not found",
-			id:           "2",
-			expectedCode: http.StatusNotFound,
+			name:         "wrong code",
+			payload:      map[string]string{"code": "000000"},
+			expectedCode: http.StatusUnprocessableEntity,
 		},
 		{
-			name:         "invalid",
-			id:           "invalid",
-			expectedCode: http.StatusBadRequest,
+			name:         "valid",
+			payload:      map[string]string{"code": code},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "already enabled",
+			payload:      map[string]string{"code": code},
+			expectedCode: http.StatusUnprocessableEntity,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			rec := httptest.NewRecorder()
-			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/lists/%s", tc.id), nil)
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+			req, _ := http.NewRequest(http.MethodPost, "/profile/mfa/confirm", b)
 			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
-			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
 
-			s.handleListsGetByID().ServeHTTP(rec, req)
+			s.handleMFAConfirm().ServeHTTP(rec, req)
 			assert.Equal(t, tc.expectedCode, rec.Code)
 		})
 	}
 }
 
-func TestServer_HandleListsEdit(t *testing.T) {
+func TestServer_HandleAPIKeysCreate(t *testing.T) {
+	store := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), store)
+	s := NewServer(NewConfig(), uc, signing.TestKeySet(t))
 	u := entity.TestUser(t)
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	store := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(store)
-	s := NewServer(NewConfig(), uc)
 	s.uc.UsersCreate(u)
 
+	expiresAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
 	testCases := []struct {
 		name         string
-		id           string
 		payload      interface{}
 		expectedCode int
 	}{
 		{
 			name: "valid",
-			id:   "1",
 			payload: map[string]string{
-				"list_title": "NEW TITLE 2",
+				"name":       "ci",
+				"scope":      "write",
+				"expires_at": expiresAt,
 			},
-			expectedCode: http.StatusOK,
+			expectedCode: http.StatusCreated,
 		},
 		{
 			name:         "invalid payload",
-			id:           "1",
 			payload:      "invalid",
 			expectedCode: http.StatusBadRequest,
 		},
 		{
-			name: "not found",
-			id:   "2",
-			payload: map[string]string{
-				"list_title": "TEST TITLE 2",
-			},
-			expectedCode: http.StatusNotFound,
-		},
-		{
-			name: "invalid id",
-			id:   "invalid",
-			payload: map[string]string{
-				"list_title": "TEST TITLE 2",
-			},
-			expectedCode: http.StatusBadRequest,
-		},
-		{
-			name: "invalid title",
-			id:   "1",
-			payload: map[string]string{
-				"list_title": "inv@li_d title *",
-			},
-			expectedCode: http.StatusUnprocessableEntity,
-		},
-		{
-			name: "existing title",
-			id:   "1",
+			name: "invalid params",
 			payload: map[string]string{
-				"list_title": "TEST TITLE 1",
+				"name":  "ci",
+				"scope": "admin",
 			},
 			expectedCode: http.StatusUnprocessableEntity,
 		},
@@ -451,22 +3438,20 @@ func TestServer_HandleListsEdit(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			l := entity.TestList(t)
-			l.UserID = u.UserID
-			s.uc.ListsCreate(l)
-			assert.NotNil(t, l.ListID)
-
 			rec := httptest.NewRecorder()
 			b := &bytes.Buffer{}
 			json.NewEncoder(b).Encode(tc.payload)
-			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/lists/%s", tc.id), b)
-			req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, u))
-			req = mux.SetURLVars(req, map[string]string{"listID": tc.id})
+			req, _ := http.NewRequest(http.MethodPost, "/api-keys", b)
+			ctx := context.WithValue(req.Context(), ctxKeyUser, u)
 
-			s.handleListsEdit().ServeHTTP(rec, req)
+			s.handleAPIKeysCreate().ServeHTTP(rec, req.WithContext(ctx))
 			assert.Equal(t, tc.expectedCode, rec.Code)
-
-			s.uc.ListsDelete(l)
+			if tc.expectedCode == http.StatusCreated {
+				res := map[string]interface{}{}
+				json.NewDecoder(rec.Body).Decode(&res)
+				assert.True(t, entity.IsAPIKey(res["key"].(string)))
+				assert.NotContains(t, res, "key_hash")
+			}
 		})
 	}
 }
@@ -1,22 +1,46 @@
 package apiserver
 
-import "os"
+import (
+	"fmt"
+	"net/netip"
+	"time"
+)
 
 type Config struct {
-	BindAddr  string `toml:"bind_addr"`
-	SecretKey string
-	LogLevel  string `toml:"log_level"`
+	BindAddr          string        `toml:"bind_addr"`
+	LogLevel          string        `toml:"log_level"`
+	ReadHeaderTimeout time.Duration `toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `toml:"read_timeout"`
+	WriteTimeout      time.Duration `toml:"write_timeout"`
+	IdleTimeout       time.Duration `toml:"idle_timeout"`
+	ShutdownDelay     time.Duration `toml:"shutdown_delay"`
+	ShutdownTimeout   time.Duration `toml:"shutdown_timeout"`
+	TrustedProxies    []string      `toml:"trusted_proxies"`
 }
 
 func NewConfig() *Config {
-	sk := os.Getenv("SECRET_KEY")
-	if sk == "" {
-		sk = "Hj/XWYwIXa9JIXQmZzIHjoMuD/LQiA+omQjUhQx3QmHA/VcyJNFCn8btu3Vh3kGw"
+	return &Config{
+		BindAddr:          ":8080",
+		LogLevel:          "debug",
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownDelay:     5 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
 	}
+}
 
-	return &Config{
-		BindAddr:  ":8080",
-		SecretKey: sk,
-		LogLevel:  "debug",
+// NewTrustedProxies parses the CIDRs of the trusted proxies,
+// so a typo fails the start instead of silently trusting nobody.
+func NewTrustedProxies(config *Config) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, len(config.TrustedProxies))
+	for n, cidr := range config.TrustedProxies {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("trusted_proxies: %w", err)
+		}
+		proxies[n] = p.Masked()
 	}
+	return proxies, nil
 }
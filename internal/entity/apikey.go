@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// APIKeyPrefix starts every API key, which tells them apart from JWTs.
+	APIKeyPrefix = "todo_"
+
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+
+	apiKeyPrefixLen = len(APIKeyPrefix) + 8
+	maxAPIKeyTTL    = 366 * 24 * time.Hour
+)
+
+// APIKey authenticates scripts as the user until it expires.
+// Only the hash of the key is stored, its prefix identifies it.
+type APIKey struct {
+	APIKeyID   int      `json:"api_key_id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scope      string   `json:"scope"`
+	ExpiresAt  *TimeISO `json:"expires_at"`
+	LastUsedAt *TimeISO `json:"last_used_at,omitempty"`
+	KeyHash    string   `json:"-"`
+	UserID     int      `json:"-"`
+}
+
+func (k *APIKey) Validate() error {
+	k.Name = strings.Join(strings.Fields(k.Name), " ")
+
+	return validation.ValidateStruct(
+		k,
+		validation.Field(&k.Name, validation.Required, validation.RuneLength(0, 50)),
+		validation.Field(&k.Scope, validation.Required, validation.In(ScopeRead, ScopeWrite)),
+		validation.Field(&k.ExpiresAt, validation.Required, validation.By(inFuture(maxAPIKeyTTL))),
+	)
+}
+
+// Generate sets a new key and returns it,
+// the key is shown to the user once and never stored.
+func (k *APIKey) Generate() (string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return "", err
+	}
+
+	key := APIKeyPrefix + token
+	k.Prefix = key[:apiKeyPrefixLen]
+	k.KeyHash = HashToken(key)
+	return key, nil
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt.Time)
+}
+
+// Allows reports whether the scope of the key permits requests
+// with the method, read keys can't change anything.
+func (k *APIKey) Allows(method string) bool {
+	if k.Scope == ScopeWrite {
+		return true
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// IsAPIKey reports whether the bearer token is an API key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -0,0 +1,99 @@
+package entity_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		k       func() *entity.APIKey
+		isValid bool
+	}{
+		{
+			name: "valid",
+			k: func() *entity.APIKey {
+				return entity.TestAPIKey(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty name",
+			k: func() *entity.APIKey {
+				k := entity.TestAPIKey(t)
+				k.Name = "  "
+				return k
+			},
+			isValid: false,
+		},
+		{
+			name: "unknown scope",
+			k: func() *entity.APIKey {
+				k := entity.TestAPIKey(t)
+				k.Scope = "admin"
+				return k
+			},
+			isValid: false,
+		},
+		{
+			name: "without expiry",
+			k: func() *entity.APIKey {
+				k := entity.TestAPIKey(t)
+				k.ExpiresAt = nil
+				return k
+			},
+			isValid: false,
+		},
+		{
+			name: "expired",
+			k: func() *entity.APIKey {
+				k := entity.TestAPIKey(t)
+				k.ExpiresAt = &entity.TimeISO{Time: time.Now().Add(-time.Minute)}
+				return k
+			},
+			isValid: false,
+		},
+		{
+			name: "too long",
+			k: func() *entity.APIKey {
+				k := entity.TestAPIKey(t)
+				k.ExpiresAt = &entity.TimeISO{Time: time.Now().Add(2 * 365 * 24 * time.Hour)}
+				return k
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.k().Validate())
+			} else {
+				assert.Error(t, tc.k().Validate())
+			}
+		})
+	}
+}
+
+func TestAPIKey_Generate(t *testing.T) {
+	k := entity.TestAPIKey(t)
+	key, err := k.Generate()
+	assert.NoError(t, err)
+	assert.True(t, entity.IsAPIKey(key))
+	assert.Equal(t, key[:len(k.Prefix)], k.Prefix)
+	assert.Equal(t, entity.HashToken(key), k.KeyHash)
+}
+
+func TestAPIKey_Allows(t *testing.T) {
+	k := entity.TestAPIKey(t)
+	assert.True(t, k.Allows(http.MethodGet))
+	assert.False(t, k.Allows(http.MethodPost))
+
+	k.Scope = entity.ScopeWrite
+	assert.True(t, k.Allows(http.MethodDelete))
+}
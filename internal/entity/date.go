@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultDateLayout = "2006-01-02"
+)
+
+// Date is a calendar day without a time of day, used for "all day" deadlines.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%s"`, d.Time.Format(defaultDateLayout))), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	date, err := time.Parse(`"`+defaultDateLayout+`"`, string(data))
+	if err != nil {
+		return err
+	}
+
+	d.Time = date
+	return nil
+}
+
+// ParseDate parses a day given as YYYY-MM-DD.
+func ParseDate(s string) (*Date, error) {
+	date, err := time.Parse(defaultDateLayout, s)
+	if err != nil {
+		return nil, err
+	}
+	return &Date{date}, nil
+}
+
+// Today returns the current day in the given location.
+func Today(loc *time.Location) *Date {
+	y, m, d := time.Now().In(loc).Date()
+	return &Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// EndOfDay returns the moment the day is over in the given location.
+func (d *Date) EndOfDay(loc *time.Location) time.Time {
+	y, m, day := d.Time.Date()
+	return time.Date(y, m, day+1, 0, 0, 0, 0, loc)
+}
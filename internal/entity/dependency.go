@@ -0,0 +1,7 @@
+package entity
+
+// Dependency means the task can't be finished before its blocker is done.
+type Dependency struct {
+	TaskID    int `json:"task_id"`
+	BlockerID int `json:"blocker_id"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// EmailVerification confirms the user owns the email before it expires.
+// Only the hash of its token is stored.
+type EmailVerification struct {
+	VerificationID int
+	UserID         int
+	TokenHash      string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+// NewEmailVerification returns the verification for the user and its token,
+// which is sent to the user and never stored.
+func NewEmailVerification(userID int, ttl time.Duration) (*EmailVerification, string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &EmailVerification{
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, token, nil
+}
+
+// Usable reports whether the verification hasn't expired at now.
+func (v *EmailVerification) Usable(now time.Time) bool {
+	return now.Before(v.ExpiresAt)
+}
@@ -0,0 +1,17 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmailVerification(t *testing.T) {
+	v, token, err := entity.NewEmailVerification(1, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.HashToken(token), v.TokenHash)
+	assert.True(t, v.Usable(v.CreatedAt))
+	assert.False(t, v.Usable(v.ExpiresAt))
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestUser(t *testing.T) *User {
 	return &User{
@@ -14,3 +17,75 @@ func TestList(t *testing.T) *List {
 		ListTitle: "TEST TITLE 1",
 	}
 }
+
+func TestLabel(t *testing.T) *Label {
+	return &Label{
+		LabelTitle: "urgent",
+		Color:      "#ff0000",
+	}
+}
+
+func TestTask(t *testing.T) *Task {
+	return &Task{
+		TaskTitle: "test task 1",
+		Details:   "details",
+		Deadline:  &TimeISO{time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestItem(t *testing.T) *Item {
+	return &Item{
+		ItemTitle: "test item 1",
+	}
+}
+
+func TestReminder(t *testing.T) *Reminder {
+	offset := 60
+	return &Reminder{
+		Offset: &offset,
+	}
+}
+
+func TestView(t *testing.T) *View {
+	return &View{
+		ViewTitle: "urgent this week",
+		Filter: &TaskFilter{
+			MinPriority: PriorityHigh,
+			Sort:        TaskSortPriority,
+		},
+	}
+}
+
+func TestTemplate(t *testing.T) *Template {
+	return &Template{
+		TemplateTitle: "release checklist",
+		Tasks: []*TemplateTask{
+			{
+				TaskTitle: "freeze",
+				DueDay:    0,
+				DueTime:   "10:00",
+				Items:     []string{"create branch"},
+			},
+			{
+				TaskTitle: "publish",
+				DueDay:    2,
+			},
+		},
+	}
+}
+
+func TestAPIKey(t *testing.T) *APIKey {
+	return &APIKey{
+		Name:      "ci",
+		Scope:     ScopeRead,
+		ExpiresAt: &TimeISO{time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)},
+	}
+}
+
+func TestMFAKey(t *testing.T) []byte {
+	key, err := GenerateMFAKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type Item struct {
+	ItemID    int    `json:"item_id"`
+	ItemTitle string `json:"item_title"`
+	Done      bool   `json:"done"`
+	Position  int    `json:"position"`
+	TaskID    int    `json:"task_id"`
+}
+
+func (i *Item) Validate() error {
+	i.ItemTitle = strings.TrimSpace(i.ItemTitle)
+
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.ItemTitle, validation.Required, validation.Length(0, 100)),
+	)
+}
@@ -0,0 +1,43 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItem_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		title   string
+		isValid bool
+	}{
+		{
+			name:    "valid",
+			title:   "buy milk",
+			isValid: true,
+		},
+		{
+			name:    "empty",
+			title:   " ",
+			isValid: false,
+		},
+		{
+			name:    "long title",
+			title:   "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			i := &entity.Item{ItemTitle: tc.title}
+			if tc.isValid {
+				assert.NoError(t, i.Validate())
+			} else {
+				assert.Error(t, i.Validate())
+			}
+		})
+	}
+}
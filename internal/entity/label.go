@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type Label struct {
+	LabelID    int    `json:"label_id"`
+	LabelTitle string `json:"label_title"`
+	Color      string `json:"color"`
+	UserID     int    `json:"user_id,omitempty"`
+}
+
+func (l *Label) Validate() error {
+	l.LabelTitle = strings.Join(strings.Fields(l.LabelTitle), " ")
+	l.Color = strings.ToLower(l.Color)
+
+	return validation.ValidateStruct(
+		l,
+		validation.Field(
+			&l.LabelTitle,
+			validation.Required,
+			validation.Match(regexp.MustCompile(`^[\p{L}\p{N} _-]+$`)),
+			validation.RuneLength(0, 30)),
+		validation.Field(
+			&l.Color,
+			validation.Required,
+			validation.Match(regexp.MustCompile(`^#[0-9a-f]{6}$`))),
+	)
+}
@@ -0,0 +1,97 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabel_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		l       func() *entity.Label
+		isValid bool
+	}{
+		{
+			name: "valid",
+			l: func() *entity.Label {
+				return entity.TestLabel(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "cyrillic title",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.LabelTitle = "срочно"
+				return l
+			},
+			isValid: true,
+		},
+		{
+			name: "upper case color",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.Color = "#FF00AA"
+				return l
+			},
+			isValid: true,
+		},
+		{
+			name: "empty title",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.LabelTitle = "  "
+				return l
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid title",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.LabelTitle = "urgent!"
+				return l
+			},
+			isValid: false,
+		},
+		{
+			name: "long title",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.LabelTitle = "fffffffffffffffffffffffffffffff"
+				return l
+			},
+			isValid: false,
+		},
+		{
+			name: "empty color",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.Color = ""
+				return l
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid color",
+			l: func() *entity.Label {
+				l := entity.TestLabel(t)
+				l.Color = "red"
+				return l
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.l().Validate())
+			} else {
+				assert.Error(t, tc.l().Validate())
+			}
+		})
+	}
+}
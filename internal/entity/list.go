@@ -8,21 +8,35 @@ import (
 )
 
 type List struct {
-	ListID    int    `json:"list_id"`
-	ListTitle string `json:"list_title"`
-	UserID    int    `json:"user_id,omitempty"`
+	ListID            int      `json:"list_id"`
+	ListTitle         string   `json:"list_title"`
+	UserID            int      `json:"user_id,omitempty"`
+	Position          string   `json:"position"`
+	RemainingEstimate int      `json:"remaining_estimate"`
+	ArchivedAt        *TimeISO `json:"archived_at,omitempty"`
 }
 
 func (l *List) Validate() error {
 	l.ListTitle = strings.Join(strings.Fields(l.ListTitle), " ")
-	l.ListTitle = strings.ToUpper(l.ListTitle)
 
 	return validation.ValidateStruct(
 		l,
 		validation.Field(
 			&l.ListTitle,
 			validation.Required,
-			validation.Match(regexp.MustCompile(`^[0-9A-Z ]+$`)),
-			validation.Length(0, 50)),
+			validation.Match(regexp.MustCompile(`^[\p{L}\p{M}\p{N} .,:;!?'"()&+#/_«»№–—-]+$`)),
+			validation.RuneLength(0, 50)),
 	)
 }
+
+// NormalizedTitle is the title in lower case,
+// titles of the lists of a user are unique regardless of case.
+func (l *List) NormalizedTitle() string {
+	return strings.ToLower(l.ListTitle)
+}
+
+// Archived reports whether the list is archived,
+// the tasks of archived lists are read-only.
+func (l *List) Archived() bool {
+	return l.ArchivedAt != nil
+}
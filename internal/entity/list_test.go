@@ -23,11 +23,26 @@ func TestList_Validate(t *testing.T) {
 			title:   "development    OF THE Zhivoi Zvuk Club 2 ",
 			isValid: true,
 		},
+		{
+			name:    "cyrillic",
+			title:   "Разработка «Живого звука» №2",
+			isValid: true,
+		},
+		{
+			name:    "punctuation",
+			title:   "Release 1.2: QA, docs & notes (draft)",
+			isValid: true,
+		},
 		{
 			name:    "invalid symbols",
 			title:   "ITMO ?#@*&%!",
 			isValid: false,
 		},
+		{
+			name:    "markup",
+			title:   "<b>JOB</b>",
+			isValid: false,
+		},
 		{
 			name:    "empty",
 			title:   "",
@@ -51,3 +66,10 @@ func TestList_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestList_ValidateKeepsCase(t *testing.T) {
+	l := &entity.List{ListTitle: "  Домашние   Дела "}
+	assert.NoError(t, l.Validate())
+	assert.Equal(t, "Домашние Дела", l.ListTitle)
+	assert.Equal(t, "домашние дела", l.NormalizedTitle())
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// LoginAttempt records a password login or a second factor check,
+// failed or not, so repeated failures can be throttled and audited.
+type LoginAttempt struct {
+	AttemptID int       `json:"attempt_id"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	UserID    *int      `json:"user_id,omitempty"`
+	Succeeded bool      `json:"succeeded"`
+	MFA       bool      `json:"mfa"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginFailures counts failed login attempts and tells when the last one was.
+type LoginFailures struct {
+	Count int
+	Last  time.Time
+}
+
+// LockedUntil returns when the next attempt is allowed. The first free
+// failures are not throttled, then every failure doubles the lockout
+// starting from base, up to max.
+func (f *LoginFailures) LockedUntil(free int, base, max time.Duration) time.Time {
+	if f.Count < free {
+		return time.Time{}
+	}
+
+	lockout := base
+	for i := free; i < f.Count && lockout < max; i++ {
+		lockout *= 2
+	}
+	if lockout > max {
+		lockout = max
+	}
+
+	return f.Last.Add(lockout)
+}
@@ -0,0 +1,47 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginFailures_LockedUntil(t *testing.T) {
+	last := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		count    int
+		expected time.Time
+	}{
+		{
+			name:     "free",
+			count:    4,
+			expected: time.Time{},
+		},
+		{
+			name:     "first lockout",
+			count:    5,
+			expected: last.Add(time.Minute),
+		},
+		{
+			name:     "doubled",
+			count:    7,
+			expected: last.Add(4 * time.Minute),
+		},
+		{
+			name:     "max",
+			count:    100,
+			expected: last.Add(time.Hour),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &entity.LoginFailures{Count: tc.count, Last: last}
+			assert.Equal(t, tc.expected, f.LockedUntil(5, time.Minute, time.Hour))
+		})
+	}
+}
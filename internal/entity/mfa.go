@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodesCount = 10
+	mfaKeySize         = 32
+)
+
+var (
+	errMFAKeySize      = errors.New("mfa key must be 32 bytes")
+	errSealedSecretLen = errors.New("sealed secret is too short")
+)
+
+// MFA is the TOTP second factor of the user. It guards the login
+// only after the user confirms it with a first code.
+type MFA struct {
+	UserID          int
+	EncryptedSecret string
+	EnabledAt       *time.Time
+	LastStep        int64
+}
+
+func (m *MFA) Enabled() bool {
+	return m.EnabledAt != nil
+}
+
+// RecoveryCode logs the user in once instead of a TOTP code.
+// Only the hash of the code is stored.
+type RecoveryCode struct {
+	CodeID   int
+	UserID   int
+	CodeHash string
+	UsedAt   *time.Time
+}
+
+// NewRecoveryCodes returns the recovery codes for the user and the codes
+// themselves, which are shown to the user once and never stored.
+func NewRecoveryCodes(userID int) ([]*RecoveryCode, []string, error) {
+	codes := make([]*RecoveryCode, recoveryCodesCount)
+	plain := make([]string, recoveryCodesCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		plain[i] = code[:8] + "-" + code[8:]
+		codes[i] = &RecoveryCode{
+			UserID:   userID,
+			CodeHash: HashRecoveryCode(plain[i]),
+		}
+	}
+	return codes, plain, nil
+}
+
+// HashRecoveryCode hashes the code ignoring its case and dashes.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return HashToken(code)
+}
+
+// GenerateMFAKey returns a random key to encrypt the TOTP secrets with.
+func GenerateMFAKey() ([]byte, error) {
+	key := make([]byte, mfaKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParseMFAKey decodes the base64 encoded key the TOTP secrets are encrypted with.
+func ParseMFAKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != mfaKeySize {
+		return nil, errMFAKeySize
+	}
+	return key, nil
+}
+
+// SealSecret encrypts the secret with AES-GCM under the key.
+func SealSecret(key []byte, secret string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret decrypts a secret sealed by SealSecret.
+func OpenSecret(key []byte, sealed string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) < aead.NonceSize() {
+		return "", errSealedSecretLen
+	}
+
+	secret, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != mfaKeySize {
+		return nil, errMFAKeySize
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the base32 form of the RFC 6238 test secret "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	testCases := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			code, err := entity.TOTPCode(rfcSecret, entity.TOTPStep(time.Unix(tc.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, code)
+		})
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := entity.TOTPStep(now)
+
+	testCases := []struct {
+		name    string
+		step    int64
+		isValid bool
+	}{
+		{name: "current", step: step, isValid: true},
+		{name: "previous", step: step - 1, isValid: true},
+		{name: "next", step: step + 1, isValid: true},
+		{name: "too old", step: step - 2, isValid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, _ := entity.TOTPCode(rfcSecret, tc.step)
+			matched, ok := entity.MatchTOTP(rfcSecret, code, now)
+			assert.Equal(t, tc.isValid, ok)
+			if tc.isValid {
+				assert.Equal(t, tc.step, matched)
+			}
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := entity.TOTPURI(rfcSecret, "todo-app", "user@example.org")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/todo-app:user@example.org?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=todo-app")
+}
+
+func TestSealSecret(t *testing.T) {
+	key := []byte(strings.Repeat("k", 32))
+
+	sealed, err := entity.SealSecret(key, rfcSecret)
+	assert.NoError(t, err)
+	assert.NotContains(t, sealed, rfcSecret)
+
+	secret, err := entity.OpenSecret(key, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, rfcSecret, secret)
+
+	_, err = entity.OpenSecret([]byte(strings.Repeat("o", 32)), sealed)
+	assert.Error(t, err)
+
+	_, err = entity.SealSecret([]byte("short"), rfcSecret)
+	assert.Error(t, err)
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, plain, err := entity.NewRecoveryCodes(1)
+	assert.NoError(t, err)
+	assert.Len(t, codes, len(plain))
+	assert.NotEqual(t, plain[0], plain[1])
+
+	assert.Equal(t, codes[0].CodeHash, entity.HashRecoveryCode(plain[0]))
+	assert.Equal(t, codes[0].CodeHash, entity.HashRecoveryCode(strings.ToUpper(plain[0])))
+	assert.Equal(t, codes[0].CodeHash, entity.HashRecoveryCode(strings.ReplaceAll(plain[0], "-", "")))
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "fmt"
+
+// Notification tells the user about the task whose reminder is due.
+type Notification struct {
+	User     *User
+	Task     *Task
+	Reminder *Reminder
+}
+
+func (n *Notification) Subject() string {
+	return fmt.Sprintf("Reminder: %s", n.Task.TaskTitle)
+}
+
+// Text mentions when the task is due in the user's time zone.
+func (n *Notification) Text() string {
+	loc := n.User.Location()
+	if n.Task.Deadline != nil {
+		return fmt.Sprintf("%s is due %s.", n.Task.TaskTitle, n.Task.Deadline.Time.In(loc).Format("2006-01-02 15:04 MST"))
+	}
+	if n.Task.DueDate != nil {
+		return fmt.Sprintf("%s is due on %s.", n.Task.TaskTitle, n.Task.DueDate.Format(defaultDateLayout))
+	}
+	return fmt.Sprintf("%s needs your attention.", n.Task.TaskTitle)
+}
@@ -0,0 +1,141 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errInvalidHash = errors.New("invalid argon2id hash")
+
+// PasswordHasher hashes the passwords of users.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// NeedsRehash reports whether the hash was made
+	// by another algorithm or with other parameters.
+	NeedsRehash(hash string) bool
+}
+
+// passwordHasher hashes new passwords. It defaults to the cheapest bcrypt
+// cost, SetPasswordHasher should be called with the configured one.
+var passwordHasher PasswordHasher = &BcryptHasher{Cost: bcrypt.MinCost}
+
+// SetPasswordHasher sets how new passwords are hashed.
+// It isn't safe to call while passwords are being hashed.
+func SetPasswordHasher(h PasswordHasher) error {
+	dummy, err := h.Hash("dummy password")
+	if err != nil {
+		return err
+	}
+
+	passwordHasher = h
+	dummyPassword = dummy
+	return nil
+}
+
+// comparePassword checks the password against a hash of any
+// supported algorithm, whichever hasher is set.
+func comparePassword(hash, password string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return compareArgon2id(hash, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher hashes passwords with argon2id, Memory is in KiB.
+// The hashes are encoded in the PHC string format.
+type Argon2idHasher struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, argon2KeyLen)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.Memory,
+		h.Time,
+		h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := parseArgon2id(hash)
+	return err != nil || *params != *h
+}
+
+func compareArgon2id(hash, password string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func parseArgon2id(hash string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	return params, salt, key, nil
+}
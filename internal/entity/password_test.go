@@ -0,0 +1,63 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	testCases := []struct {
+		name   string
+		hasher entity.PasswordHasher
+		other  entity.PasswordHasher
+	}{
+		{
+			name:   "bcrypt",
+			hasher: &entity.BcryptHasher{Cost: bcrypt.MinCost},
+			other:  &entity.BcryptHasher{Cost: bcrypt.MinCost + 1},
+		},
+		{
+			name:   "argon2id",
+			hasher: &entity.Argon2idHasher{Memory: 64, Time: 1, Threads: 1},
+			other:  &entity.Argon2idHasher{Memory: 128, Time: 1, Threads: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := tc.hasher.Hash("password")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, "$"))
+
+			u := &entity.User{EncryptedPassword: hash}
+			assert.True(t, u.ComparePassword("password"))
+			assert.False(t, u.ComparePassword("wrong"))
+
+			assert.False(t, tc.hasher.NeedsRehash(hash))
+			assert.True(t, tc.other.NeedsRehash(hash))
+		})
+	}
+
+	argon2Hash, _ := testCases[1].hasher.Hash("password")
+	assert.True(t, testCases[0].hasher.NeedsRehash(argon2Hash))
+	assert.True(t, testCases[1].hasher.NeedsRehash("invalid"))
+	assert.False(t, (&entity.User{EncryptedPassword: "$argon2id$v=19$invalid"}).ComparePassword("password"))
+}
+
+func TestUser_Rehash(t *testing.T) {
+	u := entity.TestUser(t)
+	assert.NoError(t, u.BeforeCreate())
+	assert.False(t, u.NeedsRehash())
+
+	assert.NoError(t, entity.SetPasswordHasher(&entity.Argon2idHasher{Memory: 64, Time: 1, Threads: 1}))
+	defer entity.SetPasswordHasher(&entity.BcryptHasher{Cost: bcrypt.MinCost})
+
+	assert.True(t, u.NeedsRehash())
+	assert.NoError(t, u.Rehash(u.Password))
+	assert.False(t, u.NeedsRehash())
+	assert.True(t, u.ComparePassword(u.Password))
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// PasswordReset lets the user set a new password once before it expires.
+// Only the hash of its token is stored.
+type PasswordReset struct {
+	ResetID   int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewPasswordReset returns the reset for the user and its token,
+// which is sent to the user and never stored.
+func NewPasswordReset(userID int, ttl time.Duration) (*PasswordReset, string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &PasswordReset{
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}, token, nil
+}
+
+// Usable reports whether the reset is neither used nor expired at now.
+func (p *PasswordReset) Usable(now time.Time) bool {
+	return p.UsedAt == nil && now.Before(p.ExpiresAt)
+}
+
+// NewToken returns a random URL safe token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken hashes a random token for storage,
+// a fast hash is enough as tokens can't be guessed.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,53 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPasswordReset(t *testing.T) {
+	p, token, err := entity.NewPasswordReset(1, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, p.TokenHash)
+	assert.Equal(t, entity.HashToken(token), p.TokenHash)
+
+	_, other, _ := entity.NewPasswordReset(1, time.Hour)
+	assert.NotEqual(t, token, other)
+}
+
+func TestPasswordReset_Usable(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	usedAt := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name     string
+		p        *entity.PasswordReset
+		expected bool
+	}{
+		{
+			name:     "usable",
+			p:        &entity.PasswordReset{ExpiresAt: now.Add(time.Minute)},
+			expected: true,
+		},
+		{
+			name:     "expired",
+			p:        &entity.PasswordReset{ExpiresAt: now},
+			expected: false,
+		},
+		{
+			name:     "used",
+			p:        &entity.PasswordReset{ExpiresAt: now.Add(time.Minute), UsedAt: &usedAt},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.p.Usable(now))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "strings"
+
+// positionDigits are ordered the same way byte-wise and as digits,
+// so positions compare correctly as plain strings (COLLATE "C" in postgres).
+const positionDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// PositionBetween returns a fractional key that sorts strictly between a and b.
+// An empty a stands for the beginning and an empty b for the end, so moving an
+// element never requires renumbering its neighbours. Keys never end with the
+// zero digit, otherwise nothing could be placed right before them.
+func PositionBetween(a, b string) string {
+	if b != "" && a >= b {
+		// neighbours share a key after a concurrent insert, stay right after a
+		return a + string(positionDigits[len(positionDigits)/2])
+	}
+	return midpoint(a, b)
+}
+
+func midpoint(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(positionDigits, a[0])
+	}
+
+	digitB := len(positionDigits)
+	if b != "" {
+		digitB = strings.IndexByte(positionDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(positionDigits[(digitA+digitB+1)/2])
+	}
+
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(positionDigits[digitA]) + midpoint(rest, "")
+}
+
+func digitAt(s string, n int) byte {
+	if n < len(s) {
+		return s[n]
+	}
+	return positionDigits[0]
+}
@@ -0,0 +1,86 @@
+package entity_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionBetween(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "first",
+			b:    "i",
+		},
+		{
+			name: "last",
+			a:    "i",
+		},
+		{
+			name: "adjacent digits",
+			a:    "i",
+			b:    "j",
+		},
+		{
+			name: "common prefix",
+			a:    "i1",
+			b:    "i2",
+		},
+		{
+			name: "prefix of the other",
+			a:    "i",
+			b:    "i1",
+		},
+		{
+			name: "backfilled keys",
+			a:    "00000001i",
+			b:    "00000002i",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := entity.PositionBetween(tc.a, tc.b)
+			assert.Greater(t, p, tc.a)
+			if tc.b != "" {
+				assert.Less(t, p, tc.b)
+			}
+			assert.NotEqual(t, byte('0'), p[len(p)-1])
+		})
+	}
+}
+
+func TestPositionBetween_RandomInserts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	positions := []string{}
+
+	for n := 0; n < 500; n++ {
+		i := r.Intn(len(positions) + 1)
+
+		a, b := "", ""
+		if i > 0 {
+			a = positions[i-1]
+		}
+		if i < len(positions) {
+			b = positions[i]
+		}
+
+		p := entity.PositionBetween(a, b)
+		positions = append(positions[:i], append([]string{p}, positions[i:]...)...)
+	}
+
+	assert.True(t, sort.StringsAreSorted(positions))
+	for n := 1; n < len(positions); n++ {
+		assert.NotEqual(t, positions[n-1], positions[n])
+	}
+}
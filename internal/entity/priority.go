@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Priority is stored as a number, so that tasks can be ordered by it,
+// and serialized as its name.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	if name == "" {
+		*p = PriorityNone
+		return nil
+	}
+
+	for i, n := range priorityNames {
+		if n == name {
+			*p = Priority(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown priority %q", name)
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Reminder notifies the owner of the task at a fixed moment
+// or the given number of minutes before the task is due.
+type Reminder struct {
+	ReminderID int      `json:"reminder_id"`
+	TaskID     int      `json:"task_id"`
+	RemindAt   *TimeISO `json:"remind_at,omitempty"`
+	Offset     *int     `json:"offset,omitempty"`
+	SentAt     *TimeISO `json:"sent_at,omitempty"`
+	UserID     int      `json:"-"`
+
+	// Attempts counts the deliveries started so far,
+	// the reminder isn't due again before NextAttemptAt.
+	Attempts      int      `json:"-"`
+	NextAttemptAt *TimeISO `json:"-"`
+}
+
+// maxOffset limits how long before the task a reminder may fire to 30 days, in minutes.
+const maxOffset = 30 * 24 * 60
+
+// A failed delivery is retried after minRetryDelay,
+// doubled with every further attempt up to maxRetryDelay.
+const (
+	minRetryDelay = time.Minute
+	maxRetryDelay = time.Hour
+)
+
+func (r *Reminder) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.RemindAt, validation.By(oneOfRemindTimes(r.RemindAt == nil, r.Offset == nil))),
+		validation.Field(&r.Offset, validation.Min(0), validation.Max(maxOffset)),
+	)
+}
+
+// Time returns the moment the reminder is due for the task,
+// all day tasks are due at the end of their day in the given location.
+func (r *Reminder) Time(t *Task, loc *time.Location) time.Time {
+	if r.RemindAt != nil {
+		return r.RemindAt.Time
+	}
+	if r.Offset != nil {
+		return t.Due(loc).Add(-time.Duration(*r.Offset) * time.Minute)
+	}
+	return time.Time{}
+}
+
+// Attempt counts a delivery started at now and puts off the next one,
+// so the reminder is retried with backoff if this delivery fails.
+func (r *Reminder) Attempt(now time.Time) {
+	r.Attempts++
+
+	delay := minRetryDelay
+	for n := 1; n < r.Attempts && delay < maxRetryDelay; n++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	r.NextAttemptAt = &TimeISO{Time: now.Add(delay)}
+}
+
+// Localize converts the times of the reminder to the given location for the response.
+func (r *Reminder) Localize(loc *time.Location) {
+	if r.RemindAt != nil {
+		r.RemindAt.Time = r.RemindAt.Time.In(loc)
+	}
+	if r.SentAt != nil {
+		r.SentAt.Time = r.SentAt.Time.In(loc)
+	}
+}
@@ -0,0 +1,171 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminder_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		r       func() *entity.Reminder
+		isValid bool
+	}{
+		{
+			name: "valid",
+			r: func() *entity.Reminder {
+				return entity.TestReminder(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "remind at",
+			r: func() *entity.Reminder {
+				r := entity.TestReminder(t)
+				r.Offset = nil
+				r.RemindAt = &entity.TimeISO{Time: time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC)}
+				return r
+			},
+			isValid: true,
+		},
+		{
+			name: "without time",
+			r: func() *entity.Reminder {
+				r := entity.TestReminder(t)
+				r.Offset = nil
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "both times",
+			r: func() *entity.Reminder {
+				r := entity.TestReminder(t)
+				r.RemindAt = &entity.TimeISO{Time: time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC)}
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "negative offset",
+			r: func() *entity.Reminder {
+				r := entity.TestReminder(t)
+				offset := -1
+				r.Offset = &offset
+				return r
+			},
+			isValid: false,
+		},
+		{
+			name: "long offset",
+			r: func() *entity.Reminder {
+				r := entity.TestReminder(t)
+				offset := 30*24*60 + 1
+				r.Offset = &offset
+				return r
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.r().Validate())
+			} else {
+				assert.Error(t, tc.r().Validate())
+			}
+		})
+	}
+}
+
+func TestReminder_Time(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	remindAt := time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		r        func() *entity.Reminder
+		task     func() *entity.Task
+		expected time.Time
+	}{
+		{
+			name: "remind at",
+			r: func() *entity.Reminder {
+				return &entity.Reminder{RemindAt: &entity.TimeISO{Time: remindAt}}
+			},
+			task: func() *entity.Task {
+				return entity.TestTask(t)
+			},
+			expected: remindAt,
+		},
+		{
+			name: "before deadline",
+			r: func() *entity.Reminder {
+				return entity.TestReminder(t)
+			},
+			task: func() *entity.Task {
+				return entity.TestTask(t)
+			},
+			expected: time.Date(2026, 11, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "before end of due date",
+			r: func() *entity.Reminder {
+				return entity.TestReminder(t)
+			},
+			task: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.DueDate = &entity.Date{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+				task.Deadline = nil
+				return task
+			},
+			expected: time.Date(2026, 11, 1, 23, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.True(t, tc.expected.Equal(tc.r().Time(tc.task(), loc)))
+		})
+	}
+}
+
+func TestReminder_Attempt(t *testing.T) {
+	now := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		attempts int
+		expected time.Duration
+	}{
+		{
+			name:     "first attempt",
+			attempts: 0,
+			expected: time.Minute,
+		},
+		{
+			name:     "doubled",
+			attempts: 2,
+			expected: 4 * time.Minute,
+		},
+		{
+			name:     "capped",
+			attempts: 10,
+			expected: time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := entity.TestReminder(t)
+			r.Attempts = tc.attempts
+			r.Attempt(now)
+			assert.Equal(t, tc.attempts+1, r.Attempts)
+			assert.Equal(t, now.Add(tc.expected), r.NextAttemptAt.Time)
+		})
+	}
+}
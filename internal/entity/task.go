@@ -1,10 +1,65 @@
 package entity
 
+import (
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
 type Task struct {
-	TaskID    int     `json:"task_id"`
-	TaskTitle string  `json:"task_title"`
-	Details   string  `json:"details"`
-	Deadline  TimeISO `json:"deadline"`
-	Done      bool    `json:"done"`
-	ListID    int     `json:"list_id"`
+	TaskID    int      `json:"task_id"`
+	TaskTitle string   `json:"task_title"`
+	Details   string   `json:"details"`
+	Deadline  *TimeISO `json:"deadline,omitempty"`
+	DueDate   *Date    `json:"due_date,omitempty"`
+	Done      bool     `json:"done"`
+	Priority  Priority `json:"priority"`
+	Estimate  *int     `json:"estimate,omitempty"`
+	ListID    int      `json:"list_id"`
+	Position  string   `json:"position"`
+	Progress  Progress `json:"progress"`
+	Blocked   bool     `json:"blocked"`
+	Labels    []*Label `json:"labels"`
+}
+
+// maxEstimate limits the effort estimate of a task to 30 days, in minutes.
+const maxEstimate = 30 * 24 * 60
+
+// Progress summarizes the checklist items of a task.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func (t *Task) Validate() error {
+	t.TaskTitle = strings.TrimSpace(t.TaskTitle)
+
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.TaskTitle, validation.Required, validation.Length(0, 100)),
+		validation.Field(&t.Details, validation.Length(0, 1000)),
+		validation.Field(&t.Deadline, validation.By(oneOfDeadlines(t.Deadline == nil, t.DueDate == nil))),
+		validation.Field(&t.Priority, validation.In(PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent)),
+		validation.Field(&t.Estimate, validation.Min(0), validation.Max(maxEstimate)),
+	)
+}
+
+// Due returns the moment the task is due: the deadline itself
+// or, for an all day task, the end of its due date in the given location.
+func (t *Task) Due(loc *time.Location) time.Time {
+	if t.Deadline != nil {
+		return t.Deadline.Time
+	}
+	if t.DueDate != nil {
+		return t.DueDate.EndOfDay(loc)
+	}
+	return time.Time{}
+}
+
+// Localize converts the deadline to the given location for the response.
+func (t *Task) Localize(loc *time.Location) {
+	if t.Deadline != nil {
+		t.Deadline.Time = t.Deadline.Time.In(loc)
+	}
 }
@@ -0,0 +1,174 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		t       func() *entity.Task
+		isValid bool
+	}{
+		{
+			name: "valid",
+			t: func() *entity.Task {
+				return entity.TestTask(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "all day",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.DueDate = &entity.Date{Time: task.Deadline.Time}
+				task.Deadline = nil
+				return task
+			},
+			isValid: true,
+		},
+		{
+			name: "empty title",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.TaskTitle = "   "
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "long details",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.Details = strings.Repeat("d", 1001)
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "without deadline",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.Deadline = nil
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "both deadlines",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.DueDate = &entity.Date{Time: task.Deadline.Time}
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "with priority and estimate",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				estimate := 90
+				task.Priority = entity.PriorityHigh
+				task.Estimate = &estimate
+				return task
+			},
+			isValid: true,
+		},
+		{
+			name: "unknown priority",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				task.Priority = entity.PriorityUrgent + 1
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "negative estimate",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				estimate := -1
+				task.Estimate = &estimate
+				return task
+			},
+			isValid: false,
+		},
+		{
+			name: "long estimate",
+			t: func() *entity.Task {
+				task := entity.TestTask(t)
+				estimate := 30*24*60 + 1
+				task.Estimate = &estimate
+				return task
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.t().Validate())
+			} else {
+				assert.Error(t, tc.t().Validate())
+			}
+		})
+	}
+}
+
+func TestPriority_JSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected entity.Priority
+		isValid  bool
+	}{
+		{
+			name:     "urgent",
+			data:     `"urgent"`,
+			expected: entity.PriorityUrgent,
+			isValid:  true,
+		},
+		{
+			name:     "empty",
+			data:     `""`,
+			expected: entity.PriorityNone,
+			isValid:  true,
+		},
+		{
+			name:    "unknown",
+			data:    `"asap"`,
+			isValid: false,
+		},
+		{
+			name:    "number",
+			data:    `3`,
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var p entity.Priority
+			err := json.Unmarshal([]byte(tc.data), &p)
+			if !tc.isValid {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+
+			data, err := json.Marshal(p)
+			assert.NoError(t, err)
+			if tc.data != `""` {
+				assert.Equal(t, tc.data, string(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// TaskSortPosition keeps the manual order of tasks.
+	TaskSortPosition = "position"
+	// TaskSortPriority puts the most urgent tasks first,
+	// tasks of the same priority are ordered by their deadlines.
+	TaskSortPriority = "priority"
+)
+
+// maxDueDays limits relative due ranges to a year in both directions.
+const maxDueDays = 366
+
+// TaskFilter describes which tasks to look for.
+// Saved views keep it as JSON, so due ranges are given in days relative
+// to the current day; the fields without a JSON name are set on a query.
+type TaskFilter struct {
+	ListID      int      `json:"list_id,omitempty"`
+	LabelIDs    []int    `json:"label_ids,omitempty"`
+	AllLabels   bool     `json:"all_labels,omitempty"`
+	Done        *bool    `json:"done,omitempty"`
+	Overdue     bool     `json:"overdue,omitempty"`
+	MinPriority Priority `json:"min_priority,omitempty"`
+	DueFromDays *int     `json:"due_from_days,omitempty"`
+	DueToDays   *int     `json:"due_to_days,omitempty"`
+	Sort        string   `json:"sort,omitempty"`
+
+	UserID    int            `json:"-"`
+	Now       time.Time      `json:"-"`
+	Location  *time.Location `json:"-"`
+	DueAfter  *time.Time     `json:"-"`
+	DueBefore *time.Time     `json:"-"`
+}
+
+func (f *TaskFilter) Validate() error {
+	return validation.ValidateStruct(
+		f,
+		validation.Field(&f.LabelIDs, validation.Each(validation.Required, validation.Min(1))),
+		validation.Field(&f.Done, validation.By(doneNotOverdue(f.Done, f.Overdue))),
+		validation.Field(&f.MinPriority, validation.In(PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent)),
+		validation.Field(&f.DueFromDays, validation.Min(-maxDueDays), validation.Max(maxDueDays)),
+		validation.Field(&f.DueToDays, validation.Min(-maxDueDays), validation.Max(maxDueDays), validation.By(notBefore(f.DueFromDays))),
+		validation.Field(&f.Sort, validation.In(TaskSortPosition, TaskSortPriority)),
+	)
+}
+
+// Resolve turns the relative due range into absolute bounds: a task matches
+// when it is due after the start of the first day and not later than
+// the end of the last day, days are counted in the given location.
+func (f *TaskFilter) Resolve(now time.Time, loc *time.Location) {
+	f.Now = now
+	f.Location = loc
+
+	y, m, d := now.In(loc).Date()
+	if f.DueFromDays != nil {
+		after := time.Date(y, m, d+*f.DueFromDays, 0, 0, 0, 0, loc)
+		f.DueAfter = &after
+	}
+	if f.DueToDays != nil {
+		before := time.Date(y, m, d+*f.DueToDays+1, 0, 0, 0, 0, loc)
+		f.DueBefore = &before
+	}
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const defaultClockLayout = "15:04"
+
+// Template is a saved copy of a list. Deadlines of its tasks are kept
+// relative to the first day a task of the list was due.
+type Template struct {
+	TemplateID    int             `json:"template_id"`
+	TemplateTitle string          `json:"template_title"`
+	Tasks         []*TemplateTask `json:"tasks"`
+	UserID        int             `json:"user_id,omitempty"`
+}
+
+// TemplateTask is due DueDay days after the start of the new list,
+// at DueTime or, without it, at the end of that day.
+type TemplateTask struct {
+	TaskTitle string   `json:"task_title"`
+	Details   string   `json:"details"`
+	DueDay    int      `json:"due_day"`
+	DueTime   string   `json:"due_time,omitempty"`
+	Priority  Priority `json:"priority"`
+	Estimate  *int     `json:"estimate,omitempty"`
+	Items     []string `json:"items"`
+}
+
+func (t *Template) Validate() error {
+	t.TemplateTitle = strings.Join(strings.Fields(t.TemplateTitle), " ")
+
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.TemplateTitle, validation.Required, validation.RuneLength(0, 50)),
+		validation.Field(&t.Tasks),
+	)
+}
+
+func (tt *TemplateTask) Validate() error {
+	return validation.ValidateStruct(
+		tt,
+		validation.Field(&tt.TaskTitle, validation.Required, validation.Length(0, 100)),
+		validation.Field(&tt.Details, validation.Length(0, 1000)),
+		validation.Field(&tt.DueDay, validation.Min(0), validation.Max(maxDueDays)),
+		validation.Field(&tt.DueTime, validation.Match(regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`))),
+		validation.Field(&tt.Priority, validation.In(PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent)),
+		validation.Field(&tt.Estimate, validation.Min(0), validation.Max(maxEstimate)),
+		validation.Field(&tt.Items, validation.Each(validation.Required, validation.Length(0, 100))),
+	)
+}
+
+// NewTemplateTasks copies the tasks in their order together with the titles
+// of their checklist items, days are counted in the given location.
+func NewTemplateTasks(tasks []*Task, items map[int][]*Item, loc *time.Location) []*TemplateTask {
+	templateTasks := make([]*TemplateTask, 0, len(tasks))
+
+	var start time.Time
+	for n, task := range tasks {
+		if day := dayOf(task, loc); n == 0 || day.Before(start) {
+			start = day
+		}
+	}
+
+	for _, task := range tasks {
+		tt := &TemplateTask{
+			TaskTitle: task.TaskTitle,
+			Details:   task.Details,
+			DueDay:    int(dayOf(task, loc).Sub(start).Hours() / 24),
+			Priority:  task.Priority,
+			Estimate:  task.Estimate,
+			Items:     make([]string, 0, len(items[task.TaskID])),
+		}
+
+		if task.Deadline != nil {
+			tt.DueTime = task.Deadline.Time.In(loc).Format(defaultClockLayout)
+		}
+
+		for _, i := range items[task.TaskID] {
+			tt.Items = append(tt.Items, i.ItemTitle)
+		}
+
+		templateTasks = append(templateTasks, tt)
+	}
+
+	return templateTasks
+}
+
+// Task makes a task of the list that starts on the given day.
+func (tt *TemplateTask) Task(start *Date, loc *time.Location) *Task {
+	y, m, d := start.Time.Date()
+	day := time.Date(y, m, d+tt.DueDay, 0, 0, 0, 0, time.UTC)
+
+	t := &Task{
+		TaskTitle: tt.TaskTitle,
+		Details:   tt.Details,
+		Priority:  tt.Priority,
+		Estimate:  tt.Estimate,
+	}
+
+	if tt.DueTime == "" {
+		t.DueDate = &Date{day}
+		return t
+	}
+
+	clock, _ := time.Parse(defaultClockLayout, tt.DueTime)
+	y, m, d = day.Date()
+	t.Deadline = &TimeISO{time.Date(y, m, d, clock.Hour(), clock.Minute(), 0, 0, loc)}
+
+	return t
+}
+
+// dayOf returns the day the task is due as a UTC midnight,
+// so that days can be subtracted regardless of DST.
+func dayOf(t *Task, loc *time.Location) time.Time {
+	due := time.Time{}
+	if t.Deadline != nil {
+		due = t.Deadline.Time.In(loc)
+	} else if t.DueDate != nil {
+		due = t.DueDate.Time
+	}
+
+	y, m, d := due.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,113 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		t       func() *entity.Template
+		isValid bool
+	}{
+		{
+			name: "valid",
+			t: func() *entity.Template {
+				return entity.TestTemplate(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "empty title",
+			t: func() *entity.Template {
+				tpl := entity.TestTemplate(t)
+				tpl.TemplateTitle = ""
+				return tpl
+			},
+			isValid: false,
+		},
+		{
+			name: "empty task title",
+			t: func() *entity.Template {
+				tpl := entity.TestTemplate(t)
+				tpl.Tasks[0].TaskTitle = ""
+				return tpl
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid due time",
+			t: func() *entity.Template {
+				tpl := entity.TestTemplate(t)
+				tpl.Tasks[0].DueTime = "25:00"
+				return tpl
+			},
+			isValid: false,
+		},
+		{
+			name: "negative due day",
+			t: func() *entity.Template {
+				tpl := entity.TestTemplate(t)
+				tpl.Tasks[1].DueDay = -1
+				return tpl
+			},
+			isValid: false,
+		},
+		{
+			name: "empty item",
+			t: func() *entity.Template {
+				tpl := entity.TestTemplate(t)
+				tpl.Tasks[0].Items = []string{""}
+				return tpl
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.t().Validate())
+			} else {
+				assert.Error(t, tc.t().Validate())
+			}
+		})
+	}
+}
+
+func TestNewTemplateTasks(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Moscow")
+
+	t1 := entity.TestTask(t)
+	t1.TaskID = 1
+	t1.Deadline = &entity.TimeISO{Time: time.Date(2026, 10, 30, 7, 0, 0, 0, time.UTC)}
+	t2 := entity.TestTask(t)
+	t2.TaskID = 2
+	t2.Deadline = nil
+	t2.DueDate = &entity.Date{Time: time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)}
+	items := map[int][]*entity.Item{
+		1: {{ItemTitle: "create branch"}},
+	}
+
+	tasks := entity.NewTemplateTasks([]*entity.Task{t1, t2}, items, loc)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, 0, tasks[0].DueDay)
+	assert.Equal(t, "10:00", tasks[0].DueTime)
+	assert.Equal(t, []string{"create branch"}, tasks[0].Items)
+	assert.Equal(t, 3, tasks[1].DueDay)
+	assert.Equal(t, "", tasks[1].DueTime)
+	assert.Empty(t, tasks[1].Items)
+
+	start, _ := entity.ParseDate("2026-12-01")
+	task := tasks[0].Task(start, loc)
+	assert.Nil(t, task.DueDate)
+	assert.Equal(t, time.Date(2026, 12, 1, 7, 0, 0, 0, time.UTC), task.Deadline.Time.UTC())
+
+	task = tasks[1].Task(start, loc)
+	assert.Nil(t, task.Deadline)
+	assert.Equal(t, "2026-12-04", task.DueDate.Format("2006-01-02"))
+}
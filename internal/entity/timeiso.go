@@ -7,7 +7,7 @@ import (
 )
 
 const (
-	defaultTimeLayout = "2006-01-02T15:04:05"
+	defaultTimeLayout = time.RFC3339
 )
 
 type TimeISO struct {
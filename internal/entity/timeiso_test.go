@@ -0,0 +1,98 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeISO_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		isValid bool
+	}{
+		{
+			name:    "utc",
+			data:    `"2026-11-01T09:30:00Z"`,
+			isValid: true,
+		},
+		{
+			name:    "with offset",
+			data:    `"2026-11-01T12:30:00+03:00"`,
+			isValid: true,
+		},
+		{
+			name:    "without offset",
+			data:    `"2026-11-01T09:30:00"`,
+			isValid: false,
+		},
+		{
+			name:    "date only",
+			data:    `"2026-11-01"`,
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ti := &entity.TimeISO{}
+			if tc.isValid {
+				assert.NoError(t, json.Unmarshal([]byte(tc.data), ti))
+				assert.True(t, ti.Equal(time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)))
+			} else {
+				assert.Error(t, json.Unmarshal([]byte(tc.data), ti))
+			}
+		})
+	}
+}
+
+func TestTimeISO_MarshalJSON(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	ti := &entity.TimeISO{Time: time.Date(2026, 11, 1, 12, 30, 0, 0, loc)}
+
+	b, err := json.Marshal(ti)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2026-11-01T12:30:00+03:00"`, string(b))
+}
+
+func TestDate_JSON(t *testing.T) {
+	d := &entity.Date{}
+	assert.NoError(t, json.Unmarshal([]byte(`"2026-11-01"`), d))
+	assert.Error(t, json.Unmarshal([]byte(`"2026-11-01T09:30:00Z"`), d))
+
+	b, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2026-11-01"`, string(b))
+}
+
+func TestTask_Due(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	deadline := time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		task     *entity.Task
+		expected time.Time
+	}{
+		{
+			name:     "deadline",
+			task:     &entity.Task{Deadline: &entity.TimeISO{Time: deadline}},
+			expected: deadline,
+		},
+		{
+			name:     "all day",
+			task:     &entity.Task{DueDate: &entity.Date{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}},
+			expected: time.Date(2026, 11, 1, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.True(t, tc.task.Due(loc).Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package entity
+
+// Usage counts what a user has created, for admins.
+type Usage struct {
+	Lists     int `json:"lists"`
+	Tasks     int `json:"tasks"`
+	Labels    int `json:"labels"`
+	Views     int `json:"views"`
+	Templates int `json:"templates"`
+	APIKeys   int `json:"api_keys"`
+}
@@ -1,16 +1,33 @@
 package entity
 
 import (
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
-	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	defaultTimezone = "UTC"
+
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// dummyPassword is compared against when no user has the email,
+// so a failed login costs the same whether or not the account exists.
+var dummyPassword, _ = encryptString("dummy password")
+
 type User struct {
-	UserID            int    `json:"user_id"`
-	Email             string `json:"email"`
-	Password          string `json:"password,omitempty"`
-	EncryptedPassword string `json:"-"`
+	UserID            int      `json:"user_id"`
+	Email             string   `json:"email"`
+	Password          string   `json:"password,omitempty"`
+	EncryptedPassword string   `json:"-"`
+	Timezone          string   `json:"timezone"`
+	TokenVersion      int      `json:"-"`
+	EmailVerifiedAt   *TimeISO `json:"email_verified_at,omitempty"`
+	Role              string   `json:"role"`
+	DisabledAt        *TimeISO `json:"disabled_at,omitempty"`
 }
 
 func (u *User) Validate() error {
@@ -18,6 +35,8 @@ func (u *User) Validate() error {
 		u,
 		validation.Field(&u.Email, validation.Required, is.Email),
 		validation.Field(&u.Password, validation.By(requierdIF(u.EncryptedPassword == "")), validation.Length(6, 100)),
+		validation.Field(&u.Timezone, validation.By(requierdIF(u.UserID != 0)), validation.By(isTimezone)),
+		validation.Field(&u.Role, validation.In(RoleUser, RoleAdmin)),
 	)
 }
 
@@ -29,6 +48,14 @@ func (u *User) BeforeCreate() error {
 		}
 		u.EncryptedPassword = enc
 	}
+
+	if u.Timezone == "" {
+		u.Timezone = defaultTimezone
+	}
+
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
 	return nil
 }
 
@@ -37,13 +64,55 @@ func (u *User) Sanitize() {
 }
 
 func (u *User) ComparePassword(password string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(u.EncryptedPassword), []byte(password)) == nil
+	return comparePassword(u.EncryptedPassword, password)
 }
 
-func encryptString(s string) (string, error) {
-	b, err := bcrypt.GenerateFromPassword([]byte(s), bcrypt.MinCost)
+// NeedsRehash reports whether the password was hashed
+// with another algorithm or parameters than new ones are.
+func (u *User) NeedsRehash() bool {
+	return passwordHasher.NeedsRehash(u.EncryptedPassword)
+}
+
+// Rehash hashes the password again with the current hasher.
+func (u *User) Rehash(password string) error {
+	enc, err := encryptString(password)
+	if err != nil {
+		return err
+	}
+	u.EncryptedPassword = enc
+	return nil
+}
+
+// CompareDummyPassword takes as long as ComparePassword and always fails.
+func CompareDummyPassword(password string) bool {
+	comparePassword(dummyPassword, password)
+	return false
+}
+
+// Verified reports whether the user has confirmed the email.
+func (u *User) Verified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsAdmin reports whether the user can use the admin API.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// Disabled reports whether an admin has disabled the account.
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Location returns the user's time zone, falling back to UTC.
+func (u *User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.Timezone)
 	if err != nil {
-		return "", err
+		return time.UTC
 	}
-	return string(b), nil
+	return loc
+}
+
+func encryptString(s string) (string, error) {
+	return passwordHasher.Hash(s)
 }
@@ -48,6 +48,51 @@ func TestUser_Validate(t *testing.T) {
 			},
 			isValid: false,
 		},
+		{
+			name: "admin",
+			u: func() *entity.User {
+				u := entity.TestUser(t)
+				u.Role = entity.RoleAdmin
+				return u
+			},
+			isValid: true,
+		},
+		{
+			name: "unknown role",
+			u: func() *entity.User {
+				u := entity.TestUser(t)
+				u.Role = "root"
+				return u
+			},
+			isValid: false,
+		},
+		{
+			name: "with timezone",
+			u: func() *entity.User {
+				u := entity.TestUser(t)
+				u.Timezone = "Europe/Moscow"
+				return u
+			},
+			isValid: true,
+		},
+		{
+			name: "invalid timezone",
+			u: func() *entity.User {
+				u := entity.TestUser(t)
+				u.Timezone = "Mars/Olympus"
+				return u
+			},
+			isValid: false,
+		},
+		{
+			name: "empty timezone of existing user",
+			u: func() *entity.User {
+				u := entity.TestUser(t)
+				u.UserID = 1
+				return u
+			},
+			isValid: false,
+		},
 		{
 			name: "empty password",
 			u: func() *entity.User {
@@ -83,4 +128,14 @@ func TestUser_BeforeCreate(t *testing.T) {
 	u := entity.TestUser(t)
 	assert.NoError(t, u.BeforeCreate())
 	assert.NotEmpty(t, u.EncryptedPassword)
+	assert.Equal(t, "UTC", u.Timezone)
+}
+
+func TestUser_Location(t *testing.T) {
+	u := entity.TestUser(t)
+	u.Timezone = "Europe/Moscow"
+	assert.Equal(t, "Europe/Moscow", u.Location().String())
+
+	u.Timezone = ""
+	assert.Equal(t, "UTC", u.Location().String())
 }
@@ -1,6 +1,12 @@
 package entity
 
-import validation "github.com/go-ozzo/ozzo-validation"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
 
 func requierdIF(cond bool) validation.RuleFunc {
 	return func(value interface{}) error {
@@ -10,3 +16,71 @@ func requierdIF(cond bool) validation.RuleFunc {
 		return nil
 	}
 }
+
+func isTimezone(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(s); err != nil {
+		return errors.New("must be a valid IANA time zone")
+	}
+	return nil
+}
+
+func oneOfDeadlines(noDeadline, noDueDate bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		if noDeadline == noDueDate {
+			return errors.New("exactly one of deadline and due_date is required")
+		}
+		return nil
+	}
+}
+
+func doneNotOverdue(done *bool, overdue bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		if done != nil && *done && overdue {
+			return errors.New("done tasks can not be overdue")
+		}
+		return nil
+	}
+}
+
+func notBefore(from *int) validation.RuleFunc {
+	return func(value interface{}) error {
+		to, _ := value.(*int)
+		if from != nil && to != nil && *to < *from {
+			return errors.New("must not be before due_from_days")
+		}
+		return nil
+	}
+}
+
+func oneOfRemindTimes(noRemindAt, noOffset bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		if noRemindAt == noOffset {
+			return errors.New("exactly one of remind_at and offset is required")
+		}
+		return nil
+	}
+}
+
+func inFuture(max time.Duration) validation.RuleFunc {
+	return func(value interface{}) error {
+		t, _ := value.(*TimeISO)
+		if t == nil {
+			return nil
+		}
+
+		now := time.Now()
+		if !t.After(now) {
+			return errors.New("must be in the future")
+		}
+
+		if t.After(now.Add(max)) {
+			return fmt.Errorf("must be at most %d days ahead", int(max.Hours()/24))
+		}
+		return nil
+	}
+}
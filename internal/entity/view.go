@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// View is a saved task filter. Built-in views are not stored,
+// they are identified by a key instead of an id.
+type View struct {
+	ViewID    int         `json:"view_id,omitempty"`
+	Key       string      `json:"key,omitempty"`
+	ViewTitle string      `json:"view_title"`
+	Filter    *TaskFilter `json:"filter"`
+	UserID    int         `json:"user_id,omitempty"`
+}
+
+func (v *View) Validate() error {
+	v.ViewTitle = strings.Join(strings.Fields(v.ViewTitle), " ")
+
+	return validation.ValidateStruct(
+		v,
+		validation.Field(&v.ViewTitle, validation.Required, validation.RuneLength(0, 50)),
+		validation.Field(&v.Filter, validation.Required),
+	)
+}
+
+// BuiltinViews returns the views every user has without creating them.
+func BuiltinViews() []*View {
+	open, done := false, true
+	today, tomorrow, week := 0, 1, 7
+
+	return []*View{
+		{
+			Key:       "today",
+			ViewTitle: "Today",
+			Filter:    &TaskFilter{Done: &open, DueFromDays: &today, DueToDays: &today, Sort: TaskSortPriority},
+		},
+		{
+			Key:       "upcoming",
+			ViewTitle: "Upcoming",
+			Filter:    &TaskFilter{Done: &open, DueFromDays: &tomorrow, DueToDays: &week, Sort: TaskSortPriority},
+		},
+		{
+			Key:       "overdue",
+			ViewTitle: "Overdue",
+			Filter:    &TaskFilter{Overdue: true, Sort: TaskSortPriority},
+		},
+		{
+			Key:       "completed",
+			ViewTitle: "Completed",
+			Filter:    &TaskFilter{Done: &done},
+		},
+	}
+}
+
+// BuiltinView finds a built-in view by its key.
+func BuiltinView(key string) (*View, bool) {
+	for _, v := range BuiltinViews() {
+		if v.Key == key {
+			return v, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,140 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		v       func() *entity.View
+		isValid bool
+	}{
+		{
+			name: "valid",
+			v: func() *entity.View {
+				return entity.TestView(t)
+			},
+			isValid: true,
+		},
+		{
+			name: "due range",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				from, to := 0, 7
+				v.Filter.DueFromDays = &from
+				v.Filter.DueToDays = &to
+				return v
+			},
+			isValid: true,
+		},
+		{
+			name: "empty title",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				v.ViewTitle = " "
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "without filter",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				v.Filter = nil
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "reversed due range",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				from, to := 7, 0
+				v.Filter.DueFromDays = &from
+				v.Filter.DueToDays = &to
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "long due range",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				to := 1000
+				v.Filter.DueToDays = &to
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "done and overdue",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				done := true
+				v.Filter.Done = &done
+				v.Filter.Overdue = true
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid label",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				v.Filter.LabelIDs = []int{1, 0}
+				return v
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid sort",
+			v: func() *entity.View {
+				v := entity.TestView(t)
+				v.Filter.Sort = "title"
+				return v
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.isValid {
+				assert.NoError(t, tc.v().Validate())
+			} else {
+				assert.Error(t, tc.v().Validate())
+			}
+		})
+	}
+}
+
+func TestBuiltinViews(t *testing.T) {
+	for _, v := range entity.BuiltinViews() {
+		assert.NoError(t, v.Validate())
+
+		found, ok := entity.BuiltinView(v.Key)
+		assert.True(t, ok)
+		assert.Equal(t, v.ViewTitle, found.ViewTitle)
+	}
+
+	_, ok := entity.BuiltinView("someday")
+	assert.False(t, ok)
+}
+
+func TestTaskFilter_Resolve(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	now := time.Date(2026, 11, 1, 22, 30, 0, 0, time.UTC)
+	from, to := 0, 1
+
+	f := &entity.TaskFilter{DueFromDays: &from, DueToDays: &to}
+	f.Resolve(now, loc)
+
+	assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, loc), *f.DueAfter)
+	assert.Equal(t, time.Date(2026, 11, 4, 0, 0, 0, 0, loc), *f.DueBefore)
+	assert.Equal(t, now, f.Now)
+}
@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+
+	checkTimeout = 5 * time.Second
+)
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Check reports whether a subsystem works,
+// it should give up when the context is done.
+type Check func(ctx context.Context) error
+
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string             `json:"status"`
+	Checks map[string]*Result `json:"checks,omitempty"`
+}
+
+// OK reports whether all the checks passed.
+func (r *Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs the checks the subsystems registered
+// to tell whether the server is ready to serve requests.
+type Checker struct {
+	mu           sync.RWMutex
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		checks: make(map[string]Check),
+	}
+}
+
+// Register adds the check under the name, replacing the check
+// registered under the same name before.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// Shutdown makes the server not ready for good,
+// so the load balancer stops sending it requests.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs all the checks at once, each for up to checkTimeout.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]*Result, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			res := run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = res
+			if res.Status != StatusOK {
+				report.Status = StatusFailing
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if c.shuttingDown.Load() {
+		report.Status = StatusFailing
+		report.Checks["shutdown"] = &Result{Status: StatusFailing, Error: ErrShuttingDown.Error()}
+	}
+
+	return report
+}
+
+// run runs the check, a check that ignores
+// the context fails when the context is done.
+func run(ctx context.Context, check Check) *Result {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		return &Result{Status: StatusFailing, Error: err.Error()}
+	}
+	return &Result{Status: StatusOK}
+}
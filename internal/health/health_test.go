@@ -0,0 +1,121 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("db is down") }
+	stuck := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	testCases := []struct {
+		name           string
+		checks         map[string]health.Check
+		shutdown       bool
+		canceled       bool
+		expectedStatus string
+		expectedChecks map[string]*health.Result
+	}{
+		{
+			name:           "no checks",
+			expectedStatus: health.StatusOK,
+			expectedChecks: map[string]*health.Result{},
+		},
+		{
+			name:           "ok",
+			checks:         map[string]health.Check{"db": ok, "scheduler": ok},
+			expectedStatus: health.StatusOK,
+			expectedChecks: map[string]*health.Result{
+				"db":        {Status: health.StatusOK},
+				"scheduler": {Status: health.StatusOK},
+			},
+		},
+		{
+			name:           "failing",
+			checks:         map[string]health.Check{"db": failing, "scheduler": ok},
+			expectedStatus: health.StatusFailing,
+			expectedChecks: map[string]*health.Result{
+				"db":        {Status: health.StatusFailing, Error: "db is down"},
+				"scheduler": {Status: health.StatusOK},
+			},
+		},
+		{
+			name:           "timeout",
+			checks:         map[string]health.Check{"db": stuck},
+			canceled:       true,
+			expectedStatus: health.StatusFailing,
+			expectedChecks: map[string]*health.Result{
+				"db": {Status: health.StatusFailing, Error: context.Canceled.Error()},
+			},
+		},
+		{
+			name:           "shutting down",
+			checks:         map[string]health.Check{"db": ok},
+			shutdown:       true,
+			expectedStatus: health.StatusFailing,
+			expectedChecks: map[string]*health.Result{
+				"db":       {Status: health.StatusOK},
+				"shutdown": {Status: health.StatusFailing, Error: health.ErrShuttingDown.Error()},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := health.NewChecker()
+			for name, check := range tc.checks {
+				c.Register(name, check)
+			}
+			if tc.shutdown {
+				c.Shutdown()
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.canceled {
+				cancel()
+			}
+			defer cancel()
+
+			report := c.Ready(ctx)
+			assert.Equal(t, tc.expectedStatus, report.Status)
+			assert.Equal(t, tc.expectedStatus == health.StatusOK, report.OK())
+			assert.Equal(t, tc.expectedChecks, report.Checks)
+		})
+	}
+}
+
+func TestLatestMigration(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20230101000000_create_users.up.sql",
+		"20230101000000_create_users.down.sql",
+		"20230201000000_create_lists.up.sql",
+		"20230301000000_create_tasks.down.sql",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	version, err := health.LatestMigration(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20230201000000), version)
+
+	_, err = health.LatestMigration(t.TempDir())
+	assert.Error(t, err)
+
+	version, err = health.LatestMigration("../../migrations")
+	assert.NoError(t, err)
+	assert.NotZero(t, version)
+}
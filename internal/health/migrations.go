@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNoMigrations    = errors.New("no migrations found")
+	errDirtyMigrations = errors.New("last migration failed, schema is dirty")
+)
+
+// LatestMigration returns the version of the newest
+// up migration in the directory.
+func LatestMigration(dir string) (uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has no version", name)
+		}
+
+		if version > latest {
+			latest = version
+		}
+	}
+
+	if latest == 0 {
+		return 0, errNoMigrations
+	}
+	return latest, nil
+}
+
+// Migrations checks that the migrations were applied
+// to the db up to the version.
+func Migrations(db *sql.DB, version uint64) Check {
+	return func(ctx context.Context) error {
+		var current uint64
+		var dirty bool
+		if err := db.QueryRowContext(
+			ctx,
+			"SELECT version, dirty FROM schema_migrations",
+		).Scan(&current, &dirty); err != nil {
+			return err
+		}
+
+		if dirty {
+			return errDirtyMigrations
+		}
+
+		if current != version {
+			return fmt.Errorf("schema is at version %d, expected %d", current, version)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,16 @@
+package notify
+
+import "time"
+
+type Config struct {
+	Notifier       string        `toml:"notifier"`
+	WebhookURL     string        `toml:"webhook_url"`
+	WebhookTimeout time.Duration `toml:"webhook_timeout"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Notifier:       NotifierLog,
+		WebhookTimeout: 10 * time.Second,
+	}
+}
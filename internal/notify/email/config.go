@@ -0,0 +1,28 @@
+package email
+
+import (
+	"os"
+	"time"
+)
+
+type Config struct {
+	Sender          string `toml:"email_sender"`
+	From            string `toml:"email_from"`
+	SMTPAddr        string `toml:"smtp_addr"`
+	SMTPUsername    string `toml:"smtp_username"`
+	SMTPPassword    string
+	SMTPDialTimeout time.Duration `toml:"smtp_dial_timeout"`
+	SMTPTimeout     time.Duration `toml:"smtp_timeout"`
+	MailDir         string        `toml:"maildir"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Sender:          SenderFile,
+		From:            "todo@localhost",
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		SMTPDialTimeout: 10 * time.Second,
+		SMTPTimeout:     30 * time.Second,
+		MailDir:         "mail",
+	}
+}
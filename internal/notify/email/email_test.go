@@ -0,0 +1,211 @@
+package email_test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := email.NewTemplates()
+	assert.NoError(t, err)
+
+	u := entity.TestUser(t)
+	task := entity.TestTask(t)
+	task.Details = "<b>bring the keys</b>"
+	n := &entity.Notification{User: u, Task: task, Reminder: entity.TestReminder(t)}
+
+	m, err := templates.Render("reminder", u.Email, n)
+	assert.NoError(t, err)
+	assert.Equal(t, u.Email, m.To)
+	assert.Equal(t, "Reminder: test task 1", m.Subject)
+	assert.Contains(t, m.Text, "<b>bring the keys</b>")
+	assert.Contains(t, m.HTML, "&lt;b&gt;bring the keys&lt;/b&gt;")
+
+	_, err = templates.Render("unknown", u.Email, n)
+	assert.Error(t, err)
+}
+
+func TestFileSender_Send(t *testing.T) {
+	dir := t.TempDir()
+	s, err := email.NewFileSender(dir, "todo@example.org")
+	assert.NoError(t, err)
+
+	m := &email.Message{
+		To:      "user@example.org",
+		Subject: "Напоминание",
+		Text:    "text body",
+		HTML:    "<p>html body</p>",
+	}
+	assert.NoError(t, s.Send(m))
+
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	assert.NoError(t, err)
+
+	msg := string(data)
+	assert.True(t, strings.HasPrefix(msg, "From: todo@example.org\r\n"))
+	assert.Contains(t, msg, "To: user@example.org\r\n")
+	assert.Contains(t, msg, "Subject: =?utf-8?q?")
+	assert.Contains(t, msg, "text body")
+	assert.Contains(t, msg, "<p>html body</p>")
+}
+
+func TestNewSender(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func() *email.Config
+		isValid bool
+	}{
+		{
+			name: "file",
+			config: func() *email.Config {
+				c := email.NewConfig()
+				c.MailDir = t.TempDir()
+				return c
+			},
+			isValid: true,
+		},
+		{
+			name: "smtp",
+			config: func() *email.Config {
+				c := email.NewConfig()
+				c.Sender = email.SenderSMTP
+				c.SMTPAddr = "localhost:25"
+				return c
+			},
+			isValid: true,
+		},
+		{
+			name: "smtp without timeout",
+			config: func() *email.Config {
+				c := email.NewConfig()
+				c.Sender = email.SenderSMTP
+				c.SMTPAddr = "localhost:25"
+				c.SMTPTimeout = 0
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "smtp without server",
+			config: func() *email.Config {
+				c := email.NewConfig()
+				c.Sender = email.SenderSMTP
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "unknown",
+			config: func() *email.Config {
+				c := email.NewConfig()
+				c.Sender = "pigeon"
+				return c
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := email.NewSender(tc.config())
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	m := &email.Message{To: "user@example.org", Subject: "subject", Text: "text"}
+
+	t.Run("sent", func(t *testing.T) {
+		addr, received := testSMTPServer(t, true)
+		s := email.NewSMTPSender(addr, "", "", "todo@localhost", time.Second, time.Second)
+		assert.NoError(t, s.Send(m))
+		assert.Contains(t, <-received, "Subject: subject")
+	})
+
+	t.Run("server stops answering", func(t *testing.T) {
+		addr, _ := testSMTPServer(t, false)
+		s := email.NewSMTPSender(addr, "", "", "todo@localhost", time.Second, 100*time.Millisecond)
+
+		start := time.Now()
+		err := s.Send(m)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+// testSMTPServer accepts one connection and speaks just enough SMTP to take a message.
+// If answer is false, it greets and then never replies.
+func testSMTPServer(t *testing.T, answer bool) (string, <-chan string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost\r\n"))
+		if !answer {
+			r.ReadString('\n')
+			time.Sleep(time.Second)
+			return
+		}
+
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			cmd, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch strings.ToUpper(cmd) {
+			case "DATA":
+				conn.Write([]byte("354 go ahead\r\n"))
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				conn.Write([]byte("250 ok\r\n"))
+			case "QUIT":
+				conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+
+	return l.Addr().String(), received
+}
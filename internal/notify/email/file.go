@@ -0,0 +1,48 @@
+package email
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileSender writes emails into a maildir instead of sending them,
+// so development and tests work without a mail server.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates the tmp, new and cur folders of the maildir.
+func NewFileSender(dir, from string) (*FileSender, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FileSender{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// Send writes the message into tmp and then moves it into new,
+// so readers of the maildir never see a partial message.
+func (s *FileSender) Send(m *Message) error {
+	msg, err := m.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.%s.eml", time.Now().UnixNano(), uuid.NewString())
+	tmp := filepath.Join(s.dir, "tmp", name)
+
+	if err := os.WriteFile(tmp, msg, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, "new", name))
+}
@@ -0,0 +1,45 @@
+package email
+
+import (
+	"regexp"
+	"testing"
+)
+
+// tokenRegexp matches the tokens of entity.NewToken.
+var tokenRegexp = regexp.MustCompile(`(?:^|[^\w-])([\w-]{43})(?:$|[^\w-])`)
+
+// TestSender keeps the sent messages instead of sending them.
+type TestSender struct {
+	Messages []*Message
+}
+
+func (s *TestSender) Send(m *Message) error {
+	s.Messages = append(s.Messages, m)
+	return nil
+}
+
+// LastToken returns the token mailed in the last message.
+func (s *TestSender) LastToken(t *testing.T) string {
+	t.Helper()
+
+	if len(s.Messages) == 0 {
+		t.Fatal("no email sent")
+	}
+
+	match := tokenRegexp.FindStringSubmatch(s.Messages[len(s.Messages)-1].Text)
+	if match == nil {
+		t.Fatal("no token in the email")
+	}
+	return match[1]
+}
+
+func TestMailer(t *testing.T) (*Mailer, *TestSender) {
+	t.Helper()
+
+	sender := &TestSender{}
+	m, err := NewMailer(sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, sender
+}
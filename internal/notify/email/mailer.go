@@ -0,0 +1,28 @@
+package email
+
+// Mailer sends messages rendered from the templates.
+type Mailer struct {
+	sender    Sender
+	templates *Templates
+}
+
+func NewMailer(sender Sender) (*Mailer, error) {
+	templates, err := NewTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mailer{
+		sender:    sender,
+		templates: templates,
+	}, nil
+}
+
+// Send renders the named templates with the data and sends the message.
+func (m *Mailer) Send(name, to string, data interface{}) error {
+	msg, err := m.templates.Render(name, to, data)
+	if err != nil {
+		return err
+	}
+	return m.sender.Send(msg)
+}
@@ -0,0 +1,39 @@
+package email
+
+import "errors"
+
+const (
+	SenderSMTP = "smtp"
+	SenderFile = "file"
+)
+
+var (
+	errUnknownSender = errors.New("email_sender must be smtp or file")
+	errNoSMTPServer  = errors.New("smtp sender needs smtp_addr")
+	errSMTPTimeout   = errors.New("smtp_dial_timeout and smtp_timeout must be positive")
+)
+
+// NewSender builds the sender chosen in the config.
+func NewSender(config *Config) (Sender, error) {
+	switch config.Sender {
+	case SenderSMTP:
+		if config.SMTPAddr == "" {
+			return nil, errNoSMTPServer
+		}
+		if config.SMTPDialTimeout <= 0 || config.SMTPTimeout <= 0 {
+			return nil, errSMTPTimeout
+		}
+		return NewSMTPSender(
+			config.SMTPAddr,
+			config.SMTPUsername,
+			config.SMTPPassword,
+			config.From,
+			config.SMTPDialTimeout,
+			config.SMTPTimeout,
+		), nil
+	case SenderFile:
+		return NewFileSender(config.MailDir, config.From)
+	default:
+		return nil, errUnknownSender
+	}
+}
@@ -0,0 +1,100 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+var errNoSMTPAuth = errors.New("smtp server doesn't support AUTH")
+
+// SMTPSender sends emails through an SMTP server.
+type SMTPSender struct {
+	addr        string
+	auth        smtp.Auth
+	from        string
+	dialTimeout time.Duration
+	timeout     time.Duration
+}
+
+// NewSMTPSender authenticates only if the username is given.
+// Connecting may take up to dialTimeout and sending a message up to timeout.
+func NewSMTPSender(addr, username, password, from string, dialTimeout, timeout time.Duration) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPSender{
+		addr:        addr,
+		auth:        auth,
+		from:        from,
+		dialTimeout: dialTimeout,
+		timeout:     timeout,
+	}
+}
+
+// Send does what smtp.SendMail does, but on a connection with a deadline,
+// so a server that stops answering can't block the caller forever.
+func (s *SMTPSender) Send(m *Message) error {
+	msg, err := m.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: s.dialTimeout}
+	conn, err := dialer.Dial("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+
+	host, _, _ := net.SplitHostPort(s.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errNoSMTPAuth
+		}
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(m.To); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,90 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Templates render messages from the templates/<name>.txt and
+// templates/<name>.html files. The subject is the "subject"
+// template defined in the text file.
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+func NewTemplates() (*Templates, error) {
+	entries, err := templatesFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	for _, e := range entries {
+		path := "templates/" + e.Name()
+
+		switch {
+		case strings.HasSuffix(e.Name(), ".txt"):
+			name := strings.TrimSuffix(e.Name(), ".txt")
+			tmpl, err := texttemplate.ParseFS(templatesFS, path)
+			if err != nil {
+				return nil, err
+			}
+
+			if tmpl.Lookup("subject") == nil {
+				return nil, fmt.Errorf("email template %s has no subject", name)
+			}
+			t.text[name] = tmpl
+		case strings.HasSuffix(e.Name(), ".html"):
+			name := strings.TrimSuffix(e.Name(), ".html")
+			tmpl, err := htmltemplate.ParseFS(templatesFS, path)
+			if err != nil {
+				return nil, err
+			}
+			t.html[name] = tmpl
+		}
+	}
+	return t, nil
+}
+
+// Render builds the message to the address from the named templates.
+func (t *Templates) Render(name, to string, data interface{}) (*Message, error) {
+	text, ok := t.text[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %s", name)
+	}
+
+	m := &Message{To: to}
+
+	buf := &bytes.Buffer{}
+	if err := text.ExecuteTemplate(buf, "subject", data); err != nil {
+		return nil, err
+	}
+	m.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := text.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	m.Text = buf.String()
+
+	if html, ok := t.html[name]; ok {
+		buf.Reset()
+		if err := html.Execute(buf, data); err != nil {
+			return nil, err
+		}
+		m.HTML = buf.String()
+	}
+	return m, nil
+}
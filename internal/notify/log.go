@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier only writes notifications to the log,
+// it is meant for development.
+type LogNotifier struct {
+	logger logrus.FieldLogger
+}
+
+func NewLogNotifier(logger logrus.FieldLogger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) Notify(notification *entity.Notification) error {
+	n.logger.WithFields(logrus.Fields{
+		"user_id":     notification.User.UserID,
+		"task_id":     notification.Task.TaskID,
+		"reminder_id": notification.Reminder.ReminderID,
+	}).Info(notification.Text())
+	return nil
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+)
+
+// EmailNotifier sends notifications as emails
+// rendered from the reminder templates.
+type EmailNotifier struct {
+	mailer *email.Mailer
+}
+
+func NewEmailNotifier(mailer *email.Mailer) *EmailNotifier {
+	return &EmailNotifier{
+		mailer: mailer,
+	}
+}
+
+func (n *EmailNotifier) Notify(notification *entity.Notification) error {
+	return n.mailer.Send("reminder", notification.User.Email, notification)
+}
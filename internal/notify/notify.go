@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"errors"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	NotifierLog     = "log"
+	NotifierWebhook = "webhook"
+	NotifierEmail   = "email"
+)
+
+var (
+	errUnknownNotifier = errors.New("notifier must be log, webhook or email")
+	errNoWebhookURL    = errors.New("webhook notifier needs webhook_url")
+)
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(*entity.Notification) error
+}
+
+// NewNotifier builds the notifier chosen in the config,
+// the email notifier delivers through the mailer.
+func NewNotifier(config *Config, mailer *email.Mailer, logger logrus.FieldLogger) (Notifier, error) {
+	switch config.Notifier {
+	case NotifierLog:
+		return NewLogNotifier(logger), nil
+	case NotifierWebhook:
+		if config.WebhookURL == "" {
+			return nil, errNoWebhookURL
+		}
+		return NewWebhookNotifier(config.WebhookURL, config.WebhookTimeout), nil
+	case NotifierEmail:
+		return NewEmailNotifier(mailer), nil
+	default:
+		return nil, errUnknownNotifier
+	}
+}
@@ -0,0 +1,120 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func testNotification(t *testing.T) *entity.Notification {
+	u := entity.TestUser(t)
+	u.UserID = 1
+	u.Timezone = "UTC"
+	task := entity.TestTask(t)
+	task.TaskID = 1
+	r := entity.TestReminder(t)
+	r.ReminderID = 1
+	r.TaskID = task.TaskID
+
+	return &entity.Notification{User: u, Task: task, Reminder: r}
+}
+
+func TestNewNotifier(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func() *notify.Config
+		isValid bool
+	}{
+		{
+			name: "log",
+			config: func() *notify.Config {
+				return notify.NewConfig()
+			},
+			isValid: true,
+		},
+		{
+			name: "webhook without url",
+			config: func() *notify.Config {
+				c := notify.NewConfig()
+				c.Notifier = notify.NotifierWebhook
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "email",
+			config: func() *notify.Config {
+				c := notify.NewConfig()
+				c.Notifier = notify.NotifierEmail
+				return c
+			},
+			isValid: true,
+		},
+		{
+			name: "unknown",
+			config: func() *notify.Config {
+				c := notify.NewConfig()
+				c.Notifier = "pigeon"
+				return c
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := notify.NewNotifier(tc.config(), nil, logrus.New())
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	payload := make(map[string]interface{})
+	status := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n := notify.NewWebhookNotifier(srv.URL, time.Second)
+	assert.NoError(t, n.Notify(testNotification(t)))
+	assert.Equal(t, "user@example.org", payload["email"])
+	assert.Equal(t, "Reminder: test task 1", payload["subject"])
+
+	status = http.StatusBadGateway
+	assert.Error(t, n.Notify(testNotification(t)))
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := email.NewFileSender(dir, "todo@example.org")
+	assert.NoError(t, err)
+
+	mailer, err := email.NewMailer(sender)
+	assert.NoError(t, err)
+
+	n := notify.NewEmailNotifier(mailer)
+	assert.NoError(t, n.Notify(testNotification(t)))
+
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+)
+
+// WebhookNotifier posts notifications as JSON to the URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookPayload struct {
+	UserID   int              `json:"user_id"`
+	Email    string           `json:"email"`
+	Subject  string           `json:"subject"`
+	Text     string           `json:"text"`
+	Task     *entity.Task     `json:"task"`
+	Reminder *entity.Reminder `json:"reminder"`
+}
+
+func (n *WebhookNotifier) Notify(notification *entity.Notification) error {
+	body, err := json.Marshal(&webhookPayload{
+		UserID:   notification.User.UserID,
+		Email:    notification.User.Email,
+		Subject:  notification.Subject(),
+		Text:     notification.Text(),
+		Task:     notification.Task,
+		Reminder: notification.Reminder,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
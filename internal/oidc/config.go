@@ -0,0 +1,30 @@
+package oidc
+
+import (
+	"os"
+	"time"
+)
+
+type Config struct {
+	Issuer       string        `toml:"oidc_issuer"`
+	ClientID     string        `toml:"oidc_client_id"`
+	ClientSecret string        `toml:"oidc_client_secret"`
+	Scopes       []string      `toml:"oidc_scopes"`
+	RedirectURL  string        `toml:"oidc_redirect_url"`
+	Timeout      time.Duration `toml:"oidc_timeout"`
+}
+
+// NewConfig reads the client secret from the OIDC_CLIENT_SECRET environment
+// variable, so it can be kept out of the config file.
+func NewConfig() *Config {
+	return &Config{
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		Scopes:       []string{"openid", "email"},
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Enabled reports whether a provider is configured.
+func (c *Config) Enabled() bool {
+	return c.Issuer != ""
+}
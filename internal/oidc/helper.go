@@ -0,0 +1,180 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testKeyID = "test-key"
+
+// MockServer is a minimal OIDC provider for tests. Its authorize endpoint
+// logs Email in right away and redirects back with a code.
+type MockServer struct {
+	*httptest.Server
+	ClientID      string
+	ClientSecret  string
+	Email         string
+	EmailVerified bool
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]url.Values
+}
+
+func TestServer(t *testing.T) *MockServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &MockServer{
+		ClientID:      "todo-app",
+		ClientSecret:  "secret",
+		Email:         "user@example.org",
+		EmailVerified: true,
+		key:           key,
+		codes:         make(map[string]url.Values),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.handleDiscovery)
+	mux.HandleFunc("/authorize", m.handleAuthorize)
+	mux.HandleFunc("/token", m.handleToken)
+	mux.HandleFunc("/jwks", m.handleJWKS)
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// Config returns the config of a client of the server.
+func (m *MockServer) Config(redirectURL string) *Config {
+	config := NewConfig()
+	config.Issuer = m.URL
+	config.ClientID = m.ClientID
+	config.ClientSecret = m.ClientSecret
+	config.RedirectURL = redirectURL
+	return config
+}
+
+// Authorize follows the login redirect of the app as a browser would
+// and returns the callback URL the server redirects back to.
+func (m *MockServer) Authorize(authURL string) (string, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	res, err := client.Get(authURL)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	return res.Header.Get("Location"), nil
+}
+
+func (m *MockServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(&metadata{
+		Issuer:                m.URL,
+		AuthorizationEndpoint: m.URL + "/authorize",
+		TokenEndpoint:         m.URL + "/token",
+		JWKSURI:               m.URL + "/jwks",
+	})
+}
+
+func (m *MockServer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != m.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	code := base64.RawURLEncoding.EncodeToString(b)
+
+	m.mu.Lock()
+	m.codes[code] = q
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *MockServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+	id, _ = url.QueryUnescape(id)
+	secret, _ = url.QueryUnescape(secret)
+	if id != m.ClientID || secret != m.ClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	r.ParseForm()
+	m.mu.Lock()
+	q, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	if !ok || q.Get("redirect_uri") != r.PostForm.Get("redirect_uri") || q.Get("code_challenge") != Challenge(r.PostForm.Get("code_verifier")) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := &Claims{
+		Email:         m.Email,
+		EmailVerified: m.EmailVerified,
+		Nonce:         q.Get("nonce"),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.URL,
+			Subject:   m.Email,
+			Audience:  jwt.ClaimStrings{m.ClientID},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (m *MockServer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string][]jwk{
+		"keys": {{
+			Kty: "RSA",
+			Kid: testKeyID,
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
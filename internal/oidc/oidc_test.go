@@ -0,0 +1,76 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/oidc"
+	"github.com/stretchr/testify/assert"
+)
+
+const redirectURL = "http://localhost:8080/oidc/callback"
+
+func TestProvider_Exchange(t *testing.T) {
+	m := oidc.TestServer(t)
+	p := oidc.NewProvider(m.Config(redirectURL))
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		verifier func(string) string
+		nonce    string
+		isValid  bool
+	}{
+		{
+			name:     "valid",
+			verifier: func(v string) string { return v },
+			nonce:    "nonce",
+			isValid:  true,
+		},
+		{
+			name:     "wrong verifier",
+			verifier: func(v string) string { return v + "x" },
+			nonce:    "nonce",
+			isValid:  false,
+		},
+		{
+			name:     "wrong nonce",
+			verifier: func(v string) string { return v },
+			nonce:    "other",
+			isValid:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier, _ := oidc.NewVerifier()
+			authURL, err := p.AuthCodeURL(ctx, "state", "nonce", verifier)
+			assert.NoError(t, err)
+
+			callback, err := m.Authorize(authURL)
+			assert.NoError(t, err)
+
+			u, _ := url.Parse(callback)
+			assert.Equal(t, "state", u.Query().Get("state"))
+
+			claims, err := p.Exchange(ctx, u.Query().Get("code"), tc.verifier(verifier), tc.nonce)
+			if tc.isValid {
+				assert.NoError(t, err)
+				assert.Equal(t, m.Email, claims.Email)
+				assert.True(t, claims.EmailVerified)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_IssuerMismatch(t *testing.T) {
+	m := oidc.TestServer(t)
+	config := m.Config(redirectURL)
+	config.Issuer = m.URL + "/"
+
+	_, err := oidc.NewProvider(config).AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.Error(t, err)
+}
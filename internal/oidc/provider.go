@@ -0,0 +1,276 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	errIssuerMismatch = errors.New("discovered issuer does not match the configured one")
+	errNoIDToken      = errors.New("token response has no id_token")
+	errNonceMismatch  = errors.New("id token nonce does not match")
+	errUnknownKey     = errors.New("id token is signed with an unknown key")
+	errNoExpiry       = errors.New("id token has no expiry")
+)
+
+// Claims are the ID token claims the app relies on.
+type Claims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider signs users in with the authorization code flow and PKCE.
+// The provider metadata is discovered on first use, so the app starts
+// even if the provider is down.
+type Provider struct {
+	config *Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]crypto.PublicKey
+}
+
+func NewProvider(config *Config) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// AuthCodeURL returns the URL of the provider login page.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", p.config.RedirectURL)
+	q.Set("scope", strings.Join(p.config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", Challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return md.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange trades the code for the ID token and returns its claims
+// once the token is verified.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	res := struct {
+		IDToken string `json:"id_token"`
+	}{}
+	if err := p.do(req, &res); err != nil {
+		return nil, err
+	}
+
+	if res.IDToken == "" {
+		return nil, errNoIDToken
+	}
+	return p.verify(ctx, md, res.IDToken, nonce)
+}
+
+func (p *Provider) verify(ctx context.Context, md *metadata, idToken, nonce string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(
+		idToken,
+		&Claims{},
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.key(ctx, md, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := token.Claims.(*Claims)
+	if claims.ExpiresAt == nil {
+		return nil, errNoExpiry
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errNonceMismatch
+	}
+	return claims, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &metadata{}
+	if err := p.do(req, md); err != nil {
+		return nil, err
+	}
+
+	if md.Issuer != p.config.Issuer {
+		return nil, errIssuerMismatch
+	}
+
+	p.metadata = md
+	return md, nil
+}
+
+// key returns the provider key with the id, fetching the keys again
+// if the id is unknown as the provider may have rotated them.
+func (p *Provider) key(ctx context.Context, md *metadata, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, md.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := p.do(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, errUnknownKey
+}
+
+func (p *Provider) do(req *http.Request, v interface{}) error {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("oidc provider responded with %d: %s", res.StatusCode, body)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// jwk is a public key of a JSON Web Key Set (RFC 7517).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.Kty == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
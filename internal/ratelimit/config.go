@@ -0,0 +1,19 @@
+package ratelimit
+
+import "time"
+
+type Config struct {
+	Limiter string           `toml:"rate_limiter"`
+	Limits  map[string]Limit `toml:"rate_limits"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Limiter: LimiterMemory,
+		Limits: map[string]Limit{
+			GroupPublic:  {Requests: 30, Per: time.Minute},
+			GroupAuth:    {Requests: 600, Per: time.Minute},
+			GroupDefault: {Requests: 300, Per: time.Minute},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit lets through Requests per Per on average, and up to Burst
+// requests at once. Burst defaults to Requests.
+type Limit struct {
+	Requests int           `toml:"requests"`
+	Per      time.Duration `toml:"per"`
+	Burst    int           `toml:"burst"`
+}
+
+// Result is the state of the bucket after a request was counted.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+func (l Limit) valid() bool {
+	return l.Requests > 0 && l.Per > 0 && l.Burst >= 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the number of tokens added to the bucket per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// take refills the bucket that had tokens at updated and takes a token
+// for the request if there is one. It returns the tokens left.
+func (l Limit) take(tokens float64, updated, now time.Time) (float64, *Result) {
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(l.capacity(), tokens+elapsed*l.rate())
+	}
+
+	res := &Result{Limit: int(l.capacity())}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.seconds(1 - tokens)
+	}
+
+	res.Remaining = int(tokens)
+	res.Reset = l.seconds(l.capacity() - tokens)
+	return tokens, res
+}
+
+// full returns when the bucket that had tokens at updated is full again.
+func (l Limit) full(tokens float64, updated time.Time) time.Time {
+	return updated.Add(l.seconds(l.capacity() - tokens))
+}
+
+// seconds returns how long it takes to add the tokens to the bucket.
+func (l Limit) seconds(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate() * float64(time.Second))
+}
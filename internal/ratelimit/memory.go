@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// MemoryStore keeps the buckets in memory,
+// so every instance of the server has its own limits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.capacity(), updated: now}
+		s.buckets[key] = b
+	}
+
+	tokens, res := limit.take(b.tokens, b.updated, now)
+	b.tokens = tokens
+	b.updated = now
+	b.full = limit.full(tokens, now)
+	return res, nil
+}
+
+func (s *MemoryStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
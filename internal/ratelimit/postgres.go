@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PostgresStore keeps the buckets in the rate_limits table,
+// so all instances of the server share the limits.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+func (s *PostgresStore) Take(key string, limit Limit, now time.Time) (*Result, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"INSERT INTO rate_limits (key, tokens, updated_at, full_at) VALUES ($1, $2, $3, $3) ON CONFLICT (key) DO NOTHING",
+		key,
+		limit.capacity(),
+		now,
+	); err != nil {
+		return nil, err
+	}
+
+	var tokens float64
+	var updated time.Time
+	if err := tx.QueryRow(
+		"SELECT tokens, updated_at FROM rate_limits WHERE key = $1 FOR UPDATE",
+		key,
+	).Scan(&tokens, &updated); err != nil {
+		return nil, err
+	}
+
+	tokens, res := limit.take(tokens, updated, now)
+	if _, err := tx.Exec(
+		"UPDATE rate_limits SET tokens = $2, updated_at = $3, full_at = $4 WHERE key = $1",
+		key,
+		tokens,
+		now,
+		limit.full(tokens, now),
+	); err != nil {
+		return nil, err
+	}
+
+	return res, tx.Commit()
+}
+
+func (s *PostgresStore) Prune(now time.Time) error {
+	_, err := s.db.Exec("DELETE FROM rate_limits WHERE full_at <= $1", now)
+	return err
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	LimiterMemory   = "memory"
+	LimiterPostgres = "postgres"
+
+	// GroupPublic limits the endpoints used without authentication.
+	GroupPublic = "public"
+
+	// GroupAuth limits the requests of an IP address to the private
+	// endpoints before they are authenticated, so tokens and API keys
+	// can't be guessed at the rate of the per-user limits.
+	GroupAuth = "auth"
+
+	// GroupDefault limits the route groups that have no limit of their own.
+	GroupDefault = "default"
+
+	pruneInterval = 10 * time.Minute
+)
+
+var (
+	errUnknownLimiter = errors.New("rate_limiter must be memory or postgres")
+	errIncorrectLimit = errors.New("requests and per must be positive, burst must not be negative")
+)
+
+// Store keeps the token buckets. A full bucket is the same
+// as a missing one, so Prune may drop the full buckets.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (*Result, error)
+	Prune(now time.Time) error
+}
+
+// Limiter counts the requests of a client to a route group
+// against the limit of the group.
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+// NewLimiter builds the limiter chosen in the config,
+// the postgres limiter keeps the buckets in the db.
+func NewLimiter(config *Config, db *sql.DB) (*Limiter, error) {
+	for group, limit := range config.Limits {
+		if !limit.valid() {
+			return nil, fmt.Errorf("rate limit %s: %w", group, errIncorrectLimit)
+		}
+	}
+
+	switch config.Limiter {
+	case LimiterMemory:
+		return newLimiter(NewMemoryStore(), config.Limits), nil
+	case LimiterPostgres:
+		return newLimiter(NewPostgresStore(db), config.Limits), nil
+	default:
+		return nil, errUnknownLimiter
+	}
+}
+
+func newLimiter(store Store, limits map[string]Limit) *Limiter {
+	return &Limiter{
+		store:  store,
+		limits: limits,
+		pruned: time.Now(),
+	}
+}
+
+// Allow counts the request of the client to the group. It returns nil
+// if neither the group nor the default group is limited.
+func (l *Limiter) Allow(group, client string) (*Result, error) {
+	limit, ok := l.limits[group]
+	if !ok {
+		limit, ok = l.limits[GroupDefault]
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if err := l.prune(now); err != nil {
+		return nil, err
+	}
+
+	return l.store.Take(group+":"+client, limit, now)
+}
+
+func (l *Limiter) prune(now time.Time) error {
+	l.mu.Lock()
+	if now.Sub(l.pruned) < pruneInterval {
+		l.mu.Unlock()
+		return nil
+	}
+	l.pruned = now
+	l.mu.Unlock()
+
+	return l.store.Prune(now)
+}
@@ -0,0 +1,159 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimiter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func() *ratelimit.Config
+		isValid bool
+	}{
+		{
+			name: "memory",
+			config: func() *ratelimit.Config {
+				return ratelimit.NewConfig()
+			},
+			isValid: true,
+		},
+		{
+			name: "postgres",
+			config: func() *ratelimit.Config {
+				c := ratelimit.NewConfig()
+				c.Limiter = ratelimit.LimiterPostgres
+				return c
+			},
+			isValid: true,
+		},
+		{
+			name: "unknown limiter",
+			config: func() *ratelimit.Config {
+				c := ratelimit.NewConfig()
+				c.Limiter = "redis"
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "zero per",
+			config: func() *ratelimit.Config {
+				c := ratelimit.NewConfig()
+				c.Limits["lists"] = ratelimit.Limit{Requests: 10}
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "negative burst",
+			config: func() *ratelimit.Config {
+				c := ratelimit.NewConfig()
+				c.Limits["lists"] = ratelimit.Limit{Requests: 10, Per: time.Second, Burst: -1}
+				return c
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ratelimit.NewLimiter(tc.config(), nil)
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Second, Burst: 3}
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		res, err := s.Take("ip:127.0.0.1", limit, now)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+		assert.Equal(t, time.Duration(3-i)*time.Second, res.Reset)
+	}
+
+	res, err := s.Take("ip:127.0.0.1", limit, now.Add(500*time.Millisecond))
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	res, err = s.Take("ip:127.0.0.2", limit, now.Add(500*time.Millisecond))
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = s.Take("ip:127.0.0.1", limit, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = s.Take("ip:127.0.0.1", limit, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Minute}
+	now := time.Now()
+
+	res, err := s.Take("ip:127.0.0.1", limit, now)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	assert.NoError(t, s.Prune(now.Add(time.Second)))
+	res, err = s.Take("ip:127.0.0.1", limit, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	assert.NoError(t, s.Prune(now.Add(2*time.Minute)))
+	res, err = s.Take("ip:127.0.0.1", limit, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	c := ratelimit.NewConfig()
+	c.Limits = map[string]ratelimit.Limit{
+		"lists":                {Requests: 1, Per: time.Minute},
+		ratelimit.GroupDefault: {Requests: 2, Per: time.Minute},
+	}
+
+	l, err := ratelimit.NewLimiter(c, nil)
+	assert.NoError(t, err)
+
+	res, err := l.Allow("lists", "user:1")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Limit)
+
+	res, err = l.Allow("lists", "user:1")
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	res, err = l.Allow("tasks", "user:1")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+
+	delete(c.Limits, ratelimit.GroupDefault)
+	l, err = ratelimit.NewLimiter(c, nil)
+	assert.NoError(t, err)
+
+	res, err = l.Allow("tasks", "user:1")
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+}
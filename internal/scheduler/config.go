@@ -0,0 +1,15 @@
+package scheduler
+
+import "time"
+
+type Config struct {
+	Interval  time.Duration `toml:"reminder_interval"`
+	BatchSize int           `toml:"reminder_batch_size"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Interval:  time.Minute,
+		BatchSize: 100,
+	}
+}
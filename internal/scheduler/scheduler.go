@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPollDuration is how long sending the due reminders
+// may take before the scheduler is considered stuck.
+const maxPollDuration = 10 * time.Minute
+
+var (
+	errNotRunning     = errors.New("scheduler is not running")
+	errInvalidSetting = errors.New("reminder_interval and reminder_batch_size must be positive")
+)
+
+// Scheduler sends due reminders in the background.
+type Scheduler struct {
+	config *Config
+	uc     usecase.UseCase
+	logger logrus.FieldLogger
+
+	running atomic.Bool
+	polled  atomic.Int64
+}
+
+// NewScheduler checks the config, a zero interval would panic
+// in the ticker and a zero batch size would never finish a poll.
+func NewScheduler(config *Config, uc usecase.UseCase, logger logrus.FieldLogger) (*Scheduler, error) {
+	if config.Interval <= 0 || config.BatchSize <= 0 {
+		return nil, errInvalidSetting
+	}
+
+	return &Scheduler{
+		config: config,
+		uc:     uc,
+		logger: logger,
+	}, nil
+}
+
+// Run polls for due reminders every interval until the context is done.
+// A batch being sent when the context is done is sent in full.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.polled.Store(time.Now().UnixNano())
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fails if the scheduler is not running
+// or has not polled for reminders for too long.
+func (s *Scheduler) Check(ctx context.Context) error {
+	if !s.running.Load() {
+		return errNotRunning
+	}
+
+	since := time.Since(time.Unix(0, s.polled.Load()))
+	if since > s.config.Interval+maxPollDuration {
+		return fmt.Errorf("scheduler last polled %s ago", since.Round(time.Second))
+	}
+	return nil
+}
+
+// sendDue sends full batches until fewer reminders are due
+// or the context is done.
+func (s *Scheduler) sendDue(ctx context.Context) {
+	for {
+		s.polled.Store(time.Now().UnixNano())
+
+		sent, err := s.uc.RemindersSend(time.Now(), s.config.BatchSize)
+		if err != nil {
+			s.logger.Errorf("sending reminders: %v", err)
+		}
+
+		if sent > 0 {
+			s.logger.Infof("sent %d reminders", sent)
+		}
+
+		if sent < s.config.BatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/scheduler"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScheduler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func() *scheduler.Config
+		isValid bool
+	}{
+		{
+			name: "default",
+			config: func() *scheduler.Config {
+				return scheduler.NewConfig()
+			},
+			isValid: true,
+		},
+		{
+			name: "zero interval",
+			config: func() *scheduler.Config {
+				c := scheduler.NewConfig()
+				c.Interval = 0
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "negative interval",
+			config: func() *scheduler.Config {
+				c := scheduler.NewConfig()
+				c.Interval = -time.Minute
+				return c
+			},
+			isValid: false,
+		},
+		{
+			name: "zero batch size",
+			config: func() *scheduler.Config {
+				c := scheduler.NewConfig()
+				c.BatchSize = 0
+				return c
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := scheduler.NewScheduler(tc.config(), nil, logrus.New())
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
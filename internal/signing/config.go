@@ -0,0 +1,10 @@
+package signing
+
+type Config struct {
+	KeyFiles []string `toml:"signing_keys"`
+	Dev      bool     `toml:"dev"`
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
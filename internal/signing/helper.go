@@ -0,0 +1,14 @@
+package signing
+
+import "testing"
+
+// TestKeySet returns a set with a generated Ed25519 key.
+func TestKeySet(t *testing.T) *KeySet {
+	t.Helper()
+
+	k, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKeySet(k)
+}
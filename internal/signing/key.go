@@ -0,0 +1,138 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	errNoPEM          = errors.New("no pem block found")
+	errUnsupportedKey = errors.New("signing key must be rsa or ed25519")
+	errWeakKey        = errors.New("rsa signing key must be at least 2048 bits")
+)
+
+// Key is a private key tokens are signed with, identified by
+// the RFC 7638 thumbprint of its public key.
+type Key struct {
+	ID      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+// NewKey signs with RS256 for RSA keys and with EdDSA for Ed25519 keys.
+func NewKey(private crypto.Signer) (*Key, error) {
+	k := &Key{private: private}
+
+	switch pub := private.Public().(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, errWeakKey
+		}
+		k.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errUnsupportedKey
+	}
+
+	sum := sha256.Sum256([]byte(k.thumbprintInput()))
+	k.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return k, nil
+}
+
+// GenerateKey returns a new Ed25519 key.
+func GenerateKey() (*Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(private)
+}
+
+// ParseKey reads a PKCS #8 or PKCS #1 private key in PEM.
+func ParseKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errNoPEM
+	}
+
+	if private, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return NewKey(private)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, errUnsupportedKey
+	}
+	return NewKey(signer)
+}
+
+// Public returns the key that verifies the signatures of the key.
+func (k *Key) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// JWK returns the public key as a JSON Web Key.
+func (k *Key) JWK() *JWK {
+	jwk := &JWK{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.method.Alg(),
+	}
+
+	switch pub := k.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(pub)
+	}
+
+	return jwk
+}
+
+// thumbprintInput lists the required members of the JWK
+// in lexicographic order as RFC 7638 asks.
+func (k *Key) thumbprintInput() string {
+	jwk := k.JWK()
+	if jwk.Kty == "RSA" {
+		return fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	}
+	return fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, jwk.X)
+}
+
+// JWK is a public key as published in a JWK Set.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,91 @@
+package signing
+
+import (
+	"errors"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoKeys        = errors.New("no signing keys configured, set signing_keys")
+	errUnknownKey    = errors.New("unknown signing key")
+	errSigningMethod = errors.New("incorrect signing method")
+)
+
+// KeySet signs tokens with its first key and verifies them with any of
+// its keys. To rotate keys, put a new key first and keep the old one
+// until the tokens it signed expire.
+type KeySet struct {
+	keys []*Key
+}
+
+func NewKeySet(keys ...*Key) *KeySet {
+	return &KeySet{keys: keys}
+}
+
+// Load reads the keys from the files in the config. In dev mode a missing
+// key is replaced with a generated one, so tokens don't outlive a restart.
+func Load(config *Config) (*KeySet, error) {
+	keys := make([]*Key, 0, len(config.KeyFiles))
+	for _, name := range config.KeyFiles {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := ParseKey(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if len(keys) == 0 {
+		if !config.Dev {
+			return nil, ErrNoKeys
+		}
+
+		k, err := GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return NewKeySet(keys...), nil
+}
+
+// Sign signs the claims with the first key, naming it in the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k := ks.keys[0]
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.private)
+}
+
+// Keyfunc finds the key named in the kid header of the token,
+// to be passed to jwt.ParseWithClaims.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, k := range ks.keys {
+		if k.ID != kid {
+			continue
+		}
+
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, errSigningMethod
+		}
+		return k.Public(), nil
+	}
+	return nil, errUnknownKey
+}
+
+// JWKS returns the public keys of the set as a JWK Set.
+func (ks *KeySet) JWKS() map[string][]*JWK {
+	keys := make([]*JWK, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		keys = append(keys, k.JWK())
+	}
+	return map[string][]*JWK{"keys": keys}
+}
@@ -0,0 +1,175 @@
+package signing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/signing"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func claims() jwt.Claims {
+	return &jwt.RegisteredClaims{
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func parse(ks *signing.KeySet, tokenString string) error {
+	_, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, ks.Keyfunc)
+	return err
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	oldKey, err := signing.NewKey(rsaKey)
+	assert.NoError(t, err)
+	newKey, err := signing.GenerateKey()
+	assert.NoError(t, err)
+
+	oldToken, err := signing.NewKeySet(oldKey).Sign(claims())
+	assert.NoError(t, err)
+
+	rotated := signing.NewKeySet(newKey, oldKey)
+	newToken, err := rotated.Sign(claims())
+	assert.NoError(t, err)
+
+	assert.NoError(t, parse(rotated, oldToken))
+	assert.NoError(t, parse(rotated, newToken))
+
+	token, _, _ := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	assert.Equal(t, newKey.ID, token.Header["kid"])
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+
+	assert.Error(t, parse(signing.NewKeySet(newKey), oldToken))
+}
+
+func TestKeySet_Keyfunc(t *testing.T) {
+	ks := signing.TestKeySet(t)
+	k := ks.JWKS()["keys"][0]
+
+	testCases := []struct {
+		name  string
+		token func() string
+	}{
+		{
+			name: "hmac",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+				token.Header["kid"] = k.Kid
+				tokenString, _ := token.SignedString([]byte("secret"))
+				return tokenString
+			},
+		},
+		{
+			name: "unknown kid",
+			token: func() string {
+				tokenString, _ := signing.TestKeySet(t).Sign(claims())
+				return tokenString
+			},
+		},
+		{
+			name: "none",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, claims())
+				token.Header["kid"] = k.Kid
+				tokenString, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return tokenString
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, parse(ks, tc.token()))
+		})
+	}
+}
+
+func TestKey_JWK(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	k, err := signing.NewKey(rsaKey)
+	assert.NoError(t, err)
+
+	jwk := k.JWK()
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "RS256", jwk.Alg)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.Equal(t, k.ID, jwk.Kid)
+
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	_, err = signing.NewKey(weak)
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pkcs1 := filepath.Join(dir, "rsa.pem")
+	os.WriteFile(pkcs1, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	}), 0600)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	k, _ := signing.NewKey(edKey)
+	der, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	pkcs8 := filepath.Join(dir, "ed25519.pem")
+	os.WriteFile(pkcs8, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+
+	testCases := []struct {
+		name     string
+		config   *signing.Config
+		expected int
+		isValid  bool
+	}{
+		{
+			name:     "key files",
+			config:   &signing.Config{KeyFiles: []string{pkcs8, pkcs1}},
+			expected: 2,
+			isValid:  true,
+		},
+		{
+			name:     "dev without keys",
+			config:   &signing.Config{Dev: true},
+			expected: 1,
+			isValid:  true,
+		},
+		{
+			name:    "without keys",
+			config:  &signing.Config{},
+			isValid: false,
+		},
+		{
+			name:    "missing file",
+			config:  &signing.Config{KeyFiles: []string{filepath.Join(dir, "missing.pem")}, Dev: true},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ks, err := signing.Load(tc.config)
+			if !tc.isValid {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, ks.JWKS()["keys"], tc.expected)
+		})
+	}
+
+	ks, _ := signing.Load(&signing.Config{KeyFiles: []string{pkcs8}})
+	assert.Equal(t, k.ID, ks.JWKS()["keys"][0].Kid)
+}
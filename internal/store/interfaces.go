@@ -1,16 +1,44 @@
 package store
 
-import "github.com/AnatoliyBr/todo-app/internal/entity"
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+)
 
 type Store interface {
 	User() UserRepository
 	List() ListRepository
+	Task() TaskRepository
+	Item() ItemRepository
+	Dependency() DependencyRepository
+	Label() LabelRepository
+	View() ViewRepository
+	Template() TemplateRepository
+	Reminder() ReminderRepository
+	PasswordReset() PasswordResetRepository
+	EmailVerification() EmailVerificationRepository
+	MFA() MFARepository
+	RecoveryCode() RecoveryCodeRepository
+	APIKey() APIKeyRepository
+	LoginAttempt() LoginAttemptRepository
+	Transaction(func(Store) error) error
 }
 
 type UserRepository interface {
 	Create(*entity.User) error
 	FindByID(int) (*entity.User, error)
 	FindByEmail(string) (*entity.User, error)
+	FindAll() ([]*entity.User, error)
+	EditTimezone(*entity.User) (*entity.User, error)
+	EditPassword(*entity.User) error
+	EditPasswordHash(*entity.User) error
+	Verify(*entity.User) error
+	EditEmail(*entity.User) error
+	Delete(*entity.User) error
+	Disable(*entity.User) error
+	Enable(*entity.User) error
+	RevokeTokens(*entity.User) error
 }
 
 type ListRepository interface {
@@ -18,5 +46,114 @@ type ListRepository interface {
 	FindByID(int, int) (*entity.List, error)
 	Edit(*entity.List) (*entity.List, error)
 	Delete(*entity.List) error
-	FindByUser(int) ([]*entity.List, error)
+	FindByUser(int, bool) ([]*entity.List, error)
+	Move(*entity.List) error
+	Archive(*entity.List) error
+	IsArchived(int) (bool, error)
+}
+
+type TaskRepository interface {
+	Create(*entity.Task) error
+	FindByID(int) (*entity.Task, error)
+	Edit(*entity.Task) (*entity.Task, error)
+	Delete(*entity.Task) error
+	FindByList(int) ([]*entity.Task, error)
+	FindByFilter(*entity.TaskFilter) ([]*entity.Task, error)
+	Move(*entity.Task) error
+	RemainingEstimates(...int) (map[int]int, error)
+}
+
+type ItemRepository interface {
+	Create(*entity.Item) error
+	FindByID(int, int) (*entity.Item, error)
+	Edit(*entity.Item) (*entity.Item, error)
+	Delete(*entity.Item) error
+	FindByTask(int) ([]*entity.Item, error)
+	Reorder(int, []int) error
+	Progress(...int) (map[int]entity.Progress, error)
+}
+
+type DependencyRepository interface {
+	Create(*entity.Dependency) error
+	Delete(*entity.Dependency) error
+	FindBlockers(int) ([]int, error)
+	OpenBlockers(...int) (map[int][]int, error)
+}
+
+type LabelRepository interface {
+	Create(*entity.Label) error
+	FindByID(int, int) (*entity.Label, error)
+	Edit(*entity.Label) (*entity.Label, error)
+	Delete(*entity.Label) error
+	FindByUser(int) ([]*entity.Label, error)
+	Attach(int, int) error
+	Detach(int, int) error
+	FindByTasks(...int) (map[int][]*entity.Label, error)
+}
+
+type ViewRepository interface {
+	Create(*entity.View) error
+	FindByID(int, int) (*entity.View, error)
+	Edit(*entity.View) (*entity.View, error)
+	Delete(*entity.View) error
+	FindByUser(int) ([]*entity.View, error)
+}
+
+type TemplateRepository interface {
+	Create(*entity.Template) error
+	FindByID(int, int) (*entity.Template, error)
+	Delete(*entity.Template) error
+	FindByUser(int) ([]*entity.Template, error)
+}
+
+type ReminderRepository interface {
+	Create(*entity.Reminder) error
+	FindByID(int, int) (*entity.Reminder, error)
+	Delete(*entity.Reminder) error
+	FindByTask(int) ([]*entity.Reminder, error)
+	FindDue(time.Time, int) ([]*entity.Reminder, error)
+	MarkAttempted(*entity.Reminder) error
+	MarkSent(*entity.Reminder) error
+}
+
+type PasswordResetRepository interface {
+	Create(*entity.PasswordReset) error
+	FindByToken(string) (*entity.PasswordReset, error)
+	MarkUsed(*entity.PasswordReset) error
+}
+
+type EmailVerificationRepository interface {
+	Create(*entity.EmailVerification) error
+	FindByToken(string) (*entity.EmailVerification, error)
+	FindLastByUser(int) (*entity.EmailVerification, error)
+	DeleteByUser(int) error
+}
+
+type MFARepository interface {
+	Save(*entity.MFA) error
+	FindByUser(int) (*entity.MFA, error)
+	Enable(*entity.MFA) error
+	UseStep(*entity.MFA, int64) error
+	Delete(*entity.MFA) error
+}
+
+type RecoveryCodeRepository interface {
+	Replace(int, []*entity.RecoveryCode) error
+	Use(int, string) error
+}
+
+type APIKeyRepository interface {
+	Create(*entity.APIKey) error
+	FindByID(int, int) (*entity.APIKey, error)
+	FindByHash(string) (*entity.APIKey, error)
+	Delete(*entity.APIKey) error
+	FindByUser(int) ([]*entity.APIKey, error)
+	Touch(*entity.APIKey) error
+}
+
+type LoginAttemptRepository interface {
+	Create(*entity.LoginAttempt) error
+	FailuresByEmail(string, time.Time) (*entity.LoginFailures, error)
+	FailuresByIP(string, time.Time) (*entity.LoginFailures, error)
+	MFAFailuresByUser(int, time.Time) (*entity.LoginFailures, error)
 }
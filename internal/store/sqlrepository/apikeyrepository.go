@@ -0,0 +1,146 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type APIKeyRepository struct {
+	db Querier
+}
+
+func NewAPIKeyRepository(db Querier) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *APIKeyRepository) Create(k *entity.APIKey) error {
+	if err := k.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO api_keys (name, prefix, key_hash, scope, expires_at, user_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING api_key_id",
+		k.Name,
+		k.Prefix,
+		k.KeyHash,
+		k.Scope,
+		k.ExpiresAt.Time,
+		k.UserID,
+	).Scan(&k.APIKeyID)
+}
+
+func (r *APIKeyRepository) FindByID(apiKeyID, userID int) (*entity.APIKey, error) {
+	return r.findOne(
+		"SELECT api_key_id, name, prefix, key_hash, scope, expires_at, last_used_at, user_id FROM api_keys WHERE api_key_id = $1 AND user_id = $2",
+		apiKeyID,
+		userID,
+	)
+}
+
+func (r *APIKeyRepository) FindByHash(keyHash string) (*entity.APIKey, error) {
+	return r.findOne(
+		"SELECT api_key_id, name, prefix, key_hash, scope, expires_at, last_used_at, user_id FROM api_keys WHERE key_hash = $1",
+		keyHash,
+	)
+}
+
+func (r *APIKeyRepository) Delete(k *entity.APIKey) error {
+	_, err := r.db.Exec(
+		"DELETE FROM api_keys WHERE api_key_id = $1",
+		k.APIKeyID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) FindByUser(userID int) ([]*entity.APIKey, error) {
+	keys := make([]*entity.APIKey, 0)
+
+	rows, err := r.db.Query(
+		"SELECT api_key_id, name, prefix, key_hash, scope, expires_at, last_used_at, user_id FROM api_keys WHERE user_id = $1 ORDER BY api_key_id",
+		userID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Touch records the use of the key, at most once a minute
+// so busy scripts don't write on every request.
+func (r *APIKeyRepository) Touch(k *entity.APIKey) error {
+	var usedAt time.Time
+	err := r.db.QueryRow(
+		"UPDATE api_keys SET last_used_at = NOW() WHERE api_key_id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute') RETURNING last_used_at",
+		k.APIKeyID,
+	).Scan(&usedAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	k.LastUsedAt = &entity.TimeISO{Time: usedAt}
+	return nil
+}
+
+func (r *APIKeyRepository) findOne(query string, args ...interface{}) (*entity.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+// scanner is either *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (*entity.APIKey, error) {
+	k := &entity.APIKey{}
+	var expiresAt time.Time
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(
+		&k.APIKeyID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&k.Scope,
+		&expiresAt,
+		&lastUsedAt,
+		&k.UserID,
+	); err != nil {
+		return nil, err
+	}
+
+	k.ExpiresAt = &entity.TimeISO{Time: expiresAt}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &entity.TimeISO{Time: lastUsedAt.Time}
+	}
+	return k, nil
+}
@@ -0,0 +1,105 @@
+package sqlrepository
+
+import (
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/lib/pq"
+)
+
+type DependencyRepository struct {
+	db Querier
+}
+
+func NewDependencyRepository(db Querier) *DependencyRepository {
+	return &DependencyRepository{
+		db: db,
+	}
+}
+
+func (r *DependencyRepository) Create(d *entity.Dependency) error {
+	_, err := r.db.Exec(
+		"INSERT INTO task_dependencies (task_id, blocker_id) VALUES ($1, $2)",
+		d.TaskID,
+		d.BlockerID,
+	)
+	return err
+}
+
+func (r *DependencyRepository) Delete(d *entity.Dependency) error {
+	res, err := r.db.Exec(
+		"DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2",
+		d.TaskID,
+		d.BlockerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *DependencyRepository) FindBlockers(taskID int) ([]int, error) {
+	blockers := make([]int, 0)
+
+	rows, err := r.db.Query(
+		"SELECT blocker_id FROM task_dependencies WHERE task_id = $1 ORDER BY blocker_id",
+		taskID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blockerID int
+		if err := rows.Scan(&blockerID); err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, blockerID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blockers, nil
+}
+
+// OpenBlockers returns the blockers that are not done yet for every given task.
+func (r *DependencyRepository) OpenBlockers(taskIDs ...int) (map[int][]int, error) {
+	blockers := make(map[int][]int)
+
+	rows, err := r.db.Query(
+		`SELECT d.task_id, d.blocker_id FROM task_dependencies d
+		JOIN tasks b ON b.task_id = d.blocker_id
+		WHERE d.task_id = ANY($1) AND NOT b.done
+		ORDER BY d.task_id, d.blocker_id`,
+		pq.Array(taskIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, blockerID int
+		if err := rows.Scan(&taskID, &blockerID); err != nil {
+			return nil, err
+		}
+		blockers[taskID] = append(blockers[taskID], blockerID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blockers, nil
+}
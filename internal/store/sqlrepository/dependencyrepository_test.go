@@ -0,0 +1,119 @@
+package sqlrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyRepository_Create(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "task_dependencies")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+
+	d := &entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID}
+	assert.NoError(t, s.Dependency().Create(d))
+	assert.Error(t, s.Dependency().Create(d))
+}
+
+func TestDependencyRepository_Delete(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "task_dependencies")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+
+	d := &entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID}
+	err := s.Dependency().Delete(d)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Dependency().Create(d)
+	err = s.Dependency().Delete(d)
+	assert.NoError(t, err)
+}
+
+func TestDependencyRepository_FindBlockers(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "task_dependencies")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+
+	blockers, err := s.Dependency().FindBlockers(t2.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+
+	s.Dependency().Create(&entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID})
+	blockers, err = s.Dependency().FindBlockers(t2.TaskID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{t1.TaskID}, blockers)
+}
+
+func TestDependencyRepository_OpenBlockers(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "task_dependencies")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+	s.Dependency().Create(&entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID})
+
+	open, err := s.Dependency().OpenBlockers(t1.TaskID, t2.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, open[t1.TaskID])
+	assert.Equal(t, []int{t1.TaskID}, open[t2.TaskID])
+
+	t1.Done = true
+	s.Task().Edit(t1)
+	open, err = s.Dependency().OpenBlockers(t2.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, open[t2.TaskID])
+}
@@ -0,0 +1,72 @@
+package sqlrepository
+
+import (
+	"database/sql"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type EmailVerificationRepository struct {
+	db Querier
+}
+
+func NewEmailVerificationRepository(db Querier) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		db: db,
+	}
+}
+
+func (r *EmailVerificationRepository) Create(v *entity.EmailVerification) error {
+	return r.db.QueryRow(
+		"INSERT INTO email_verifications (token_hash, user_id, created_at, expires_at) VALUES ($1, $2, $3, $4) RETURNING verification_id",
+		v.TokenHash,
+		v.UserID,
+		v.CreatedAt,
+		v.ExpiresAt,
+	).Scan(&v.VerificationID)
+}
+
+func (r *EmailVerificationRepository) FindByToken(tokenHash string) (*entity.EmailVerification, error) {
+	return r.findOne(
+		"SELECT verification_id, token_hash, user_id, created_at, expires_at FROM email_verifications WHERE token_hash = $1",
+		tokenHash,
+	)
+}
+
+// FindLastByUser returns the verification sent to the user most recently.
+func (r *EmailVerificationRepository) FindLastByUser(userID int) (*entity.EmailVerification, error) {
+	return r.findOne(
+		"SELECT verification_id, token_hash, user_id, created_at, expires_at FROM email_verifications WHERE user_id = $1 ORDER BY created_at DESC, verification_id DESC LIMIT 1",
+		userID,
+	)
+}
+
+// DeleteByUser removes the verifications sent to the user,
+// so none of them can confirm a changed email.
+func (r *EmailVerificationRepository) DeleteByUser(userID int) error {
+	_, err := r.db.Exec(
+		"DELETE FROM email_verifications WHERE user_id = $1",
+		userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *EmailVerificationRepository) findOne(query string, arg interface{}) (*entity.EmailVerification, error) {
+	v := &entity.EmailVerification{}
+	if err := r.db.QueryRow(query, arg).Scan(
+		&v.VerificationID,
+		&v.TokenHash,
+		&v.UserID,
+		&v.CreatedAt,
+		&v.ExpiresAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/store"
 )
 
 func TestDB(t *testing.T, databaseURL string) (*sql.DB, func(...string)) {
@@ -29,3 +31,9 @@ func TestDB(t *testing.T, databaseURL string) (*sql.DB, func(...string)) {
 		db.Close()
 	}
 }
+
+func TestStore(t *testing.T, db *sql.DB) *store.AppStore {
+	t.Helper()
+
+	return NewStore(db)
+}
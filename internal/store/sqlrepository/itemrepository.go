@@ -0,0 +1,153 @@
+package sqlrepository
+
+import (
+	"database/sql"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/lib/pq"
+)
+
+type ItemRepository struct {
+	db Querier
+}
+
+func NewItemRepository(db Querier) *ItemRepository {
+	return &ItemRepository{
+		db: db,
+	}
+}
+
+func (r *ItemRepository) Create(i *entity.Item) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		`INSERT INTO items (item_title, done, position, task_id)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM items WHERE task_id = $3), $3)
+		RETURNING item_id, position`,
+		i.ItemTitle,
+		i.Done,
+		i.TaskID,
+	).Scan(&i.ItemID, &i.Position)
+}
+
+func (r *ItemRepository) FindByID(itemID, taskID int) (*entity.Item, error) {
+	i := &entity.Item{}
+	if err := r.db.QueryRow(
+		"SELECT item_id, item_title, done, position, task_id FROM items WHERE item_id = $1 AND task_id = $2",
+		itemID,
+		taskID,
+	).Scan(
+		&i.ItemID,
+		&i.ItemTitle,
+		&i.Done,
+		&i.Position,
+		&i.TaskID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *ItemRepository) Edit(i *entity.Item) (*entity.Item, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE items SET item_title = $1, done = $2 WHERE item_id = $3",
+		i.ItemTitle,
+		i.Done,
+		i.ItemID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *ItemRepository) Delete(i *entity.Item) error {
+	_, err := r.db.Exec(
+		"DELETE FROM items WHERE item_id = $1",
+		i.ItemID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ItemRepository) FindByTask(taskID int) ([]*entity.Item, error) {
+	items := make([]*entity.Item, 0)
+
+	rows, err := r.db.Query(
+		"SELECT item_id, item_title, done, position, task_id FROM items WHERE task_id = $1 ORDER BY position",
+		taskID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i := &entity.Item{}
+
+		err := rows.Scan(&i.ItemID, &i.ItemTitle, &i.Done, &i.Position, &i.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Reorder sets item positions to follow the order of itemIDs.
+func (r *ItemRepository) Reorder(taskID int, itemIDs []int) error {
+	_, err := r.db.Exec(
+		`UPDATE items SET position = o.position
+		FROM unnest($1::BIGINT[]) WITH ORDINALITY AS o(item_id, position)
+		WHERE items.item_id = o.item_id AND items.task_id = $2`,
+		pq.Array(itemIDs),
+		taskID,
+	)
+	return err
+}
+
+// Progress counts done and total items for every given task in one query.
+func (r *ItemRepository) Progress(taskIDs ...int) (map[int]entity.Progress, error) {
+	progress := make(map[int]entity.Progress, len(taskIDs))
+
+	rows, err := r.db.Query(
+		"SELECT task_id, COUNT(*) FILTER (WHERE done), COUNT(*) FROM items WHERE task_id = ANY($1) GROUP BY task_id",
+		pq.Array(taskIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var p entity.Progress
+
+		if err := rows.Scan(&taskID, &p.Done, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[taskID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
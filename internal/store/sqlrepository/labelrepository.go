@@ -0,0 +1,169 @@
+package sqlrepository
+
+import (
+	"database/sql"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/lib/pq"
+)
+
+type LabelRepository struct {
+	db Querier
+}
+
+func NewLabelRepository(db Querier) *LabelRepository {
+	return &LabelRepository{
+		db: db,
+	}
+}
+
+func (r *LabelRepository) Create(l *entity.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO labels (label_title, color, user_id) VALUES ($1, $2, $3) RETURNING label_id",
+		l.LabelTitle,
+		l.Color,
+		l.UserID,
+	).Scan(&l.LabelID)
+}
+
+func (r *LabelRepository) FindByID(labelID, userID int) (*entity.Label, error) {
+	l := &entity.Label{}
+	if err := r.db.QueryRow(
+		"SELECT label_id, label_title, color, user_id FROM labels WHERE label_id = $1 AND user_id = $2",
+		labelID,
+		userID,
+	).Scan(
+		&l.LabelID,
+		&l.LabelTitle,
+		&l.Color,
+		&l.UserID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) Edit(l *entity.Label) (*entity.Label, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE labels SET label_title = $1, color = $2 WHERE label_id = $3",
+		l.LabelTitle,
+		l.Color,
+		l.LabelID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) Delete(l *entity.Label) error {
+	_, err := r.db.Exec(
+		"DELETE FROM labels WHERE label_id = $1",
+		l.LabelID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *LabelRepository) FindByUser(userID int) ([]*entity.Label, error) {
+	labels := make([]*entity.Label, 0)
+
+	rows, err := r.db.Query(
+		"SELECT label_id, label_title, color FROM labels WHERE user_id = $1 ORDER BY label_title",
+		userID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l := &entity.Label{}
+		if err := rows.Scan(&l.LabelID, &l.LabelTitle, &l.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (r *LabelRepository) Attach(taskID, labelID int) error {
+	_, err := r.db.Exec(
+		"INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		taskID,
+		labelID,
+	)
+	return err
+}
+
+func (r *LabelRepository) Detach(taskID, labelID int) error {
+	res, err := r.db.Exec(
+		"DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2",
+		taskID,
+		labelID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return store.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindByTasks loads the labels of all given tasks with a single join.
+func (r *LabelRepository) FindByTasks(taskIDs ...int) (map[int][]*entity.Label, error) {
+	labels := make(map[int][]*entity.Label)
+
+	rows, err := r.db.Query(
+		`SELECT tl.task_id, l.label_id, l.label_title, l.color FROM task_labels tl
+		JOIN labels l ON l.label_id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY tl.task_id, l.label_title`,
+		pq.Array(taskIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		l := &entity.Label{}
+
+		if err := rows.Scan(&taskID, &l.LabelID, &l.LabelTitle, &l.Color); err != nil {
+			return nil, err
+		}
+		labels[taskID] = append(labels[taskID], l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
@@ -8,10 +8,10 @@ import (
 )
 
 type ListRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewListRepository(db *sql.DB) *ListRepository {
+func NewListRepository(db Querier) *ListRepository {
 	return &ListRepository{
 		db: db,
 	}
@@ -23,28 +23,37 @@ func (r *ListRepository) Create(l *entity.List) error {
 	}
 
 	return r.db.QueryRow(
-		"INSERT INTO lists (list_title, user_id) VALUES ($1, $2) RETURNING list_id",
+		"INSERT INTO lists (list_title, list_title_normalized, user_id, position) VALUES ($1, $2, $3, $4) RETURNING list_id",
 		l.ListTitle,
+		l.NormalizedTitle(),
 		l.UserID,
+		l.Position,
 	).Scan(&l.ListID)
 }
 
 func (r *ListRepository) FindByID(listID, userID int) (*entity.List, error) {
 	l := &entity.List{}
+	var archivedAt sql.NullTime
 	if err := r.db.QueryRow(
-		"SELECT list_id, list_title, user_id FROM lists WHERE list_id = $1 AND user_id = $2",
+		"SELECT list_id, list_title, user_id, position, archived_at FROM lists WHERE list_id = $1 AND user_id = $2",
 		listID,
 		userID,
 	).Scan(
 		&l.ListID,
 		&l.ListTitle,
 		&l.UserID,
+		&l.Position,
+		&archivedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, store.ErrRecordNotFound
 		}
 		return nil, err
 	}
+
+	if archivedAt.Valid {
+		l.ArchivedAt = &entity.TimeISO{Time: archivedAt.Time}
+	}
 	return l, nil
 }
 
@@ -54,8 +63,9 @@ func (r *ListRepository) Edit(l *entity.List) (*entity.List, error) {
 	}
 
 	_, err := r.db.Exec(
-		"UPDATE lists SET list_title = $1 WHERE list_id = $2",
+		"UPDATE lists SET list_title = $1, list_title_normalized = $2 WHERE list_id = $3",
 		l.ListTitle,
+		l.NormalizedTitle(),
 		l.ListID,
 	)
 	if err != nil {
@@ -74,12 +84,14 @@ func (r *ListRepository) Delete(l *entity.List) error {
 	return nil
 }
 
-func (r *ListRepository) FindByUser(userID int) ([]*entity.List, error) {
+// FindByUser returns either the archived lists of the user or the other ones.
+func (r *ListRepository) FindByUser(userID int, archived bool) ([]*entity.List, error) {
 	lists := make([]*entity.List, 0)
 
 	rows, err := r.db.Query(
-		"SELECT list_id, list_title FROM lists WHERE user_id = $1",
-		userID)
+		"SELECT list_id, list_title, position, archived_at FROM lists WHERE user_id = $1 AND (archived_at IS NOT NULL) = $2 ORDER BY position, list_id",
+		userID,
+		archived)
 
 	if err != nil {
 		return nil, err
@@ -88,22 +100,62 @@ func (r *ListRepository) FindByUser(userID int) ([]*entity.List, error) {
 
 	for rows.Next() {
 		var listID int
-		var listTitle string
+		var listTitle, position string
+		var archivedAt sql.NullTime
 
-		err := rows.Scan(&listID, &listTitle)
+		err := rows.Scan(&listID, &listTitle, &position, &archivedAt)
 		if err != nil {
 			return nil, err
 		}
-		lists = append(lists, &entity.List{ListID: listID, ListTitle: listTitle})
+
+		l := &entity.List{ListID: listID, ListTitle: listTitle, Position: position}
+		if archivedAt.Valid {
+			l.ArchivedAt = &entity.TimeISO{Time: archivedAt.Time}
+		}
+		lists = append(lists, l)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	if len(lists) > 0 {
-		return lists, nil
-	} else {
-		return nil, store.ErrRecordNotFound
+	return lists, nil
+}
+
+func (r *ListRepository) Move(l *entity.List) error {
+	_, err := r.db.Exec(
+		"UPDATE lists SET position = $1 WHERE list_id = $2",
+		l.Position,
+		l.ListID,
+	)
+	return err
+}
+
+// Archive stores the archived time of the list, a list without it is unarchived.
+func (r *ListRepository) Archive(l *entity.List) error {
+	var archivedAt sql.NullTime
+	if l.ArchivedAt != nil {
+		archivedAt = sql.NullTime{Time: l.ArchivedAt.Time, Valid: true}
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE lists SET archived_at = $1 WHERE list_id = $2",
+		archivedAt,
+		l.ListID,
+	)
+	return err
+}
+
+func (r *ListRepository) IsArchived(listID int) (bool, error) {
+	var archived bool
+	if err := r.db.QueryRow(
+		"SELECT archived_at IS NOT NULL FROM lists WHERE list_id = $1",
+		listID,
+	).Scan(&archived); err != nil {
+		if err == sql.ErrNoRows {
+			return false, store.ErrRecordNotFound
+		}
+		return false, err
 	}
+	return archived, nil
 }
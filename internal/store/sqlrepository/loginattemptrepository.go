@@ -0,0 +1,74 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+)
+
+type LoginAttemptRepository struct {
+	db Querier
+}
+
+func NewLoginAttemptRepository(db Querier) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+func (r *LoginAttemptRepository) Create(a *entity.LoginAttempt) error {
+	return r.db.QueryRow(
+		"INSERT INTO login_attempts (email, ip, user_id, succeeded, mfa, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING attempt_id",
+		a.Email,
+		a.IP,
+		a.UserID,
+		a.Succeeded,
+		a.MFA,
+		a.CreatedAt,
+	).Scan(&a.AttemptID)
+}
+
+// FailuresByEmail counts the failures since the last successful login.
+func (r *LoginAttemptRepository) FailuresByEmail(email string, since time.Time) (*entity.LoginFailures, error) {
+	return r.failures(
+		`SELECT COUNT(*), MAX(created_at) FROM login_attempts
+		WHERE email = $1 AND NOT succeeded AND created_at > $2
+		AND created_at > COALESCE((SELECT MAX(created_at) FROM login_attempts WHERE email = $1 AND succeeded), $2)`,
+		email,
+		since,
+	)
+}
+
+// FailuresByIP counts the failures whether or not a login has succeeded
+// since, so a client can't reset them by logging in to its own account.
+func (r *LoginAttemptRepository) FailuresByIP(ip string, since time.Time) (*entity.LoginFailures, error) {
+	return r.failures(
+		"SELECT COUNT(*), MAX(created_at) FROM login_attempts WHERE ip = $1 AND NOT succeeded AND created_at > $2",
+		ip,
+		since,
+	)
+}
+
+// MFAFailuresByUser counts the failed second factor checks since the last
+// successful one. A correct password doesn't reset them.
+func (r *LoginAttemptRepository) MFAFailuresByUser(userID int, since time.Time) (*entity.LoginFailures, error) {
+	return r.failures(
+		`SELECT COUNT(*), MAX(created_at) FROM login_attempts
+		WHERE user_id = $1 AND mfa AND NOT succeeded AND created_at > $2
+		AND created_at > COALESCE((SELECT MAX(created_at) FROM login_attempts WHERE user_id = $1 AND mfa AND succeeded), $2)`,
+		userID,
+		since,
+	)
+}
+
+func (r *LoginAttemptRepository) failures(query string, args ...interface{}) (*entity.LoginFailures, error) {
+	f := &entity.LoginFailures{}
+	var last sql.NullTime
+	if err := r.db.QueryRow(query, args...).Scan(&f.Count, &last); err != nil {
+		return nil, err
+	}
+
+	f.Last = last.Time
+	return f, nil
+}
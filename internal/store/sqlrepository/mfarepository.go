@@ -0,0 +1,111 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type MFARepository struct {
+	db Querier
+}
+
+func NewMFARepository(db Querier) *MFARepository {
+	return &MFARepository{
+		db: db,
+	}
+}
+
+// Save stores a new secret of the user, which stays disabled until Enable.
+func (r *MFARepository) Save(m *entity.MFA) error {
+	_, err := r.db.Exec(
+		`INSERT INTO user_mfa (user_id, encrypted_secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET encrypted_secret = EXCLUDED.encrypted_secret, enabled_at = NULL, last_step = 0`,
+		m.UserID,
+		m.EncryptedSecret,
+	)
+	if err != nil {
+		return err
+	}
+
+	m.EnabledAt = nil
+	m.LastStep = 0
+	return nil
+}
+
+func (r *MFARepository) FindByUser(userID int) (*entity.MFA, error) {
+	m := &entity.MFA{}
+	var enabledAt sql.NullTime
+	if err := r.db.QueryRow(
+		"SELECT user_id, encrypted_secret, enabled_at, last_step FROM user_mfa WHERE user_id = $1",
+		userID,
+	).Scan(
+		&m.UserID,
+		&m.EncryptedSecret,
+		&enabledAt,
+		&m.LastStep,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if enabledAt.Valid {
+		m.EnabledAt = &enabledAt.Time
+	}
+	return m, nil
+}
+
+func (r *MFARepository) Enable(m *entity.MFA) error {
+	var enabledAt time.Time
+	if err := r.db.QueryRow(
+		"UPDATE user_mfa SET enabled_at = NOW() WHERE user_id = $1 RETURNING enabled_at",
+		m.UserID,
+	).Scan(&enabledAt); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	m.EnabledAt = &enabledAt
+	return nil
+}
+
+// UseStep records the time step of an accepted code, it fails with
+// ErrRecordNotFound if a code of the step or a later one was used already.
+func (r *MFARepository) UseStep(m *entity.MFA, step int64) error {
+	res, err := r.db.Exec(
+		"UPDATE user_mfa SET last_step = $2 WHERE user_id = $1 AND last_step < $2",
+		m.UserID,
+		step,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return store.ErrRecordNotFound
+	}
+
+	m.LastStep = step
+	return nil
+}
+
+func (r *MFARepository) Delete(m *entity.MFA) error {
+	_, err := r.db.Exec(
+		"DELETE FROM user_mfa WHERE user_id = $1",
+		m.UserID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqlrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFARepository_Save(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "user_mfa")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	_, err := s.MFA().FindByUser(u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	m := &entity.MFA{UserID: u.UserID, EncryptedSecret: "secret"}
+	assert.NoError(t, s.MFA().Save(m))
+	assert.NoError(t, s.MFA().Enable(m))
+
+	m.EncryptedSecret = "other secret"
+	assert.NoError(t, s.MFA().Save(m))
+
+	found, err := s.MFA().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, "other secret", found.EncryptedSecret)
+	assert.False(t, found.Enabled())
+}
+
+func TestMFARepository_Enable(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "user_mfa")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	m := &entity.MFA{UserID: u.UserID, EncryptedSecret: "secret"}
+	assert.EqualError(t, s.MFA().Enable(m), store.ErrRecordNotFound.Error())
+
+	s.MFA().Save(m)
+	assert.NoError(t, s.MFA().Enable(m))
+	assert.True(t, m.Enabled())
+
+	found, err := s.MFA().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.True(t, found.Enabled())
+}
+
+func TestMFARepository_UseStep(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "user_mfa")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	m := &entity.MFA{UserID: u.UserID, EncryptedSecret: "secret"}
+	s.MFA().Save(m)
+
+	assert.NoError(t, s.MFA().UseStep(m, 10))
+	assert.Equal(t, int64(10), m.LastStep)
+	assert.EqualError(t, s.MFA().UseStep(m, 10), store.ErrRecordNotFound.Error())
+	assert.EqualError(t, s.MFA().UseStep(m, 9), store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.MFA().UseStep(m, 11))
+}
+
+func TestMFARepository_Delete(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "user_mfa")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	m := &entity.MFA{UserID: u.UserID, EncryptedSecret: "secret"}
+	s.MFA().Save(m)
+
+	assert.NoError(t, s.MFA().Delete(m))
+	_, err := s.MFA().FindByUser(u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
@@ -0,0 +1,71 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type PasswordResetRepository struct {
+	db Querier
+}
+
+func NewPasswordResetRepository(db Querier) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+func (r *PasswordResetRepository) Create(p *entity.PasswordReset) error {
+	return r.db.QueryRow(
+		"INSERT INTO password_resets (token_hash, user_id, expires_at) VALUES ($1, $2, $3) RETURNING reset_id",
+		p.TokenHash,
+		p.UserID,
+		p.ExpiresAt,
+	).Scan(&p.ResetID)
+}
+
+func (r *PasswordResetRepository) FindByToken(tokenHash string) (*entity.PasswordReset, error) {
+	p := &entity.PasswordReset{}
+	var usedAt sql.NullTime
+	if err := r.db.QueryRow(
+		"SELECT reset_id, token_hash, user_id, expires_at, used_at FROM password_resets WHERE token_hash = $1",
+		tokenHash,
+	).Scan(
+		&p.ResetID,
+		&p.TokenHash,
+		&p.UserID,
+		&p.ExpiresAt,
+		&usedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		p.UsedAt = &usedAt.Time
+	}
+	return p, nil
+}
+
+// MarkUsed uses the reset up, it fails with ErrRecordNotFound
+// if the reset has already been used.
+func (r *PasswordResetRepository) MarkUsed(p *entity.PasswordReset) error {
+	var usedAt time.Time
+	if err := r.db.QueryRow(
+		"UPDATE password_resets SET used_at = NOW() WHERE reset_id = $1 AND used_at IS NULL RETURNING used_at",
+		p.ResetID,
+	).Scan(&usedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	p.UsedAt = &usedAt
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqlrepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_Create(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "password_resets")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	p, _, _ := entity.NewPasswordReset(u.UserID, time.Hour)
+	assert.NoError(t, s.PasswordReset().Create(p))
+	assert.NotNil(t, p.ResetID)
+}
+
+func TestPasswordResetRepository_FindByToken(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "password_resets")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	p, token, _ := entity.NewPasswordReset(u.UserID, time.Hour)
+	_, err := s.PasswordReset().FindByToken(entity.HashToken(token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.PasswordReset().Create(p)
+	found, err := s.PasswordReset().FindByToken(entity.HashToken(token))
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, found.UserID)
+	assert.Nil(t, found.UsedAt)
+}
+
+func TestPasswordResetRepository_MarkUsed(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "password_resets")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	p, _, _ := entity.NewPasswordReset(u.UserID, time.Hour)
+	s.PasswordReset().Create(p)
+
+	assert.NoError(t, s.PasswordReset().MarkUsed(p))
+	assert.NotNil(t, p.UsedAt)
+
+	assert.EqualError(t, s.PasswordReset().MarkUsed(p), store.ErrRecordNotFound.Error())
+}
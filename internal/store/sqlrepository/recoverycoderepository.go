@@ -0,0 +1,59 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type RecoveryCodeRepository struct {
+	db Querier
+}
+
+func NewRecoveryCodeRepository(db Querier) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		db: db,
+	}
+}
+
+// Replace drops the codes of the user and stores the new ones,
+// so it should run in a transaction.
+func (r *RecoveryCodeRepository) Replace(userID int, codes []*entity.RecoveryCode) error {
+	if _, err := r.db.Exec(
+		"DELETE FROM recovery_codes WHERE user_id = $1",
+		userID,
+	); err != nil {
+		return err
+	}
+
+	for _, c := range codes {
+		c.UserID = userID
+		if err := r.db.QueryRow(
+			"INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2) RETURNING code_id",
+			c.UserID,
+			c.CodeHash,
+		).Scan(&c.CodeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Use uses up the code of the user, it fails with ErrRecordNotFound
+// if there is no such unused code.
+func (r *RecoveryCodeRepository) Use(userID int, codeHash string) error {
+	var usedAt time.Time
+	if err := r.db.QueryRow(
+		"UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL RETURNING used_at",
+		userID,
+		codeHash,
+	).Scan(&usedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package sqlrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryCodeRepository_Replace(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "recovery_codes")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	old, oldPlain, _ := entity.NewRecoveryCodes(u.UserID)
+	assert.NoError(t, s.RecoveryCode().Replace(u.UserID, old))
+
+	codes, plain, _ := entity.NewRecoveryCodes(u.UserID)
+	assert.NoError(t, s.RecoveryCode().Replace(u.UserID, codes))
+	assert.NotNil(t, codes[0].CodeID)
+
+	err := s.RecoveryCode().Use(u.UserID, entity.HashRecoveryCode(oldPlain[0]))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.RecoveryCode().Use(u.UserID, entity.HashRecoveryCode(plain[0])))
+}
+
+func TestRecoveryCodeRepository_Use(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "recovery_codes")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	codes, plain, _ := entity.NewRecoveryCodes(u.UserID)
+	s.RecoveryCode().Replace(u.UserID, codes)
+
+	hash := entity.HashRecoveryCode(plain[0])
+	assert.EqualError(t, s.RecoveryCode().Use(u.UserID+1, hash), store.ErrRecordNotFound.Error())
+	assert.NoError(t, s.RecoveryCode().Use(u.UserID, hash))
+	assert.EqualError(t, s.RecoveryCode().Use(u.UserID, hash), store.ErrRecordNotFound.Error())
+}
@@ -0,0 +1,156 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type ReminderRepository struct {
+	db Querier
+}
+
+func NewReminderRepository(db Querier) *ReminderRepository {
+	return &ReminderRepository{
+		db: db,
+	}
+}
+
+func (r *ReminderRepository) Create(rm *entity.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	var remindAt sql.NullTime
+	if rm.RemindAt != nil {
+		remindAt = sql.NullTime{Time: rm.RemindAt.Time, Valid: true}
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO reminders (task_id, remind_at, offset_minutes) VALUES ($1, $2, $3) RETURNING reminder_id",
+		rm.TaskID,
+		remindAt,
+		rm.Offset,
+	).Scan(&rm.ReminderID)
+}
+
+func (r *ReminderRepository) FindByID(reminderID, taskID int) (*entity.Reminder, error) {
+	rm := &entity.Reminder{}
+	var remindAt, sentAt sql.NullTime
+	if err := r.db.QueryRow(
+		"SELECT reminder_id, task_id, remind_at, offset_minutes, sent_at FROM reminders WHERE reminder_id = $1 AND task_id = $2",
+		reminderID,
+		taskID,
+	).Scan(
+		&rm.ReminderID,
+		&rm.TaskID,
+		&remindAt,
+		&rm.Offset,
+		&sentAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	setReminderTimes(rm, remindAt, sentAt)
+	return rm, nil
+}
+
+func (r *ReminderRepository) Delete(rm *entity.Reminder) error {
+	_, err := r.db.Exec(
+		"DELETE FROM reminders WHERE reminder_id = $1",
+		rm.ReminderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReminderRepository) FindByTask(taskID int) ([]*entity.Reminder, error) {
+	return r.find(
+		"SELECT reminder_id, task_id, remind_at, offset_minutes, sent_at, attempts, 0 FROM reminders WHERE task_id = $1 ORDER BY reminder_id",
+		taskID)
+}
+
+// FindDue returns up to limit unsent reminders of open tasks that are due at now
+// and locks them until the end of the transaction. Reminders locked by another
+// transaction are skipped, so several instances never pick the same reminder.
+// Reminders waiting for a retry are left out, and the least attempted come first,
+// so failing reminders can't crowd out the rest of the batch.
+func (r *ReminderRepository) FindDue(now time.Time, limit int) ([]*entity.Reminder, error) {
+	return r.find(
+		`SELECT r.reminder_id, r.task_id, r.remind_at, r.offset_minutes, r.sent_at, r.attempts, l.user_id
+		FROM reminders r
+		JOIN tasks t ON t.task_id = r.task_id
+		JOIN lists l ON l.list_id = t.list_id
+		JOIN users u ON u.user_id = l.user_id
+		WHERE r.sent_at IS NULL AND NOT t.done AND COALESCE(
+			r.remind_at,
+			COALESCE(t.deadline, (t.due_date + 1)::timestamp AT TIME ZONE u.timezone) - r.offset_minutes * INTERVAL '1 minute'
+		) <= $1 AND (r.next_attempt_at IS NULL OR r.next_attempt_at <= $1)
+		ORDER BY r.attempts, r.reminder_id
+		LIMIT $2
+		FOR UPDATE OF r SKIP LOCKED`,
+		now,
+		limit)
+}
+
+func (r *ReminderRepository) MarkAttempted(rm *entity.Reminder) error {
+	_, err := r.db.Exec(
+		"UPDATE reminders SET attempts = $1, next_attempt_at = $2 WHERE reminder_id = $3",
+		rm.Attempts,
+		rm.NextAttemptAt.Time,
+		rm.ReminderID,
+	)
+	return err
+}
+
+func (r *ReminderRepository) MarkSent(rm *entity.Reminder) error {
+	_, err := r.db.Exec(
+		"UPDATE reminders SET sent_at = $1 WHERE reminder_id = $2",
+		rm.SentAt.Time,
+		rm.ReminderID,
+	)
+	return err
+}
+
+func (r *ReminderRepository) find(query string, args ...interface{}) ([]*entity.Reminder, error) {
+	reminders := make([]*entity.Reminder, 0)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rm := &entity.Reminder{}
+		var remindAt, sentAt sql.NullTime
+
+		if err := rows.Scan(&rm.ReminderID, &rm.TaskID, &remindAt, &rm.Offset, &sentAt, &rm.Attempts, &rm.UserID); err != nil {
+			return nil, err
+		}
+
+		setReminderTimes(rm, remindAt, sentAt)
+		reminders = append(reminders, rm)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+func setReminderTimes(rm *entity.Reminder, remindAt, sentAt sql.NullTime) {
+	if remindAt.Valid {
+		rm.RemindAt = &entity.TimeISO{Time: remindAt.Time}
+	}
+	if sentAt.Valid {
+		rm.SentAt = &entity.TimeISO{Time: sentAt.Time}
+	}
+}
@@ -0,0 +1,151 @@
+package sqlrepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminderRepository_Create(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "reminders")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+
+	r := entity.TestReminder(t)
+	r.TaskID = task.TaskID
+	assert.NoError(t, s.Reminder().Create(r))
+	assert.NotNil(t, r.ReminderID)
+
+	r = entity.TestReminder(t)
+	r.TaskID = task.TaskID
+	r.Offset = nil
+	assert.Error(t, s.Reminder().Create(r))
+}
+
+func TestReminderRepository_FindByID(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "reminders")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+
+	r := entity.TestReminder(t)
+	r.TaskID = task.TaskID
+	_, err := s.Reminder().FindByID(1, task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Reminder().Create(r)
+	found, err := s.Reminder().FindByID(r.ReminderID, task.TaskID)
+	assert.NoError(t, err)
+	assert.Equal(t, *r.Offset, *found.Offset)
+
+	_, err = s.Reminder().FindByID(r.ReminderID, task.TaskID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestReminderRepository_Delete(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "reminders")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+
+	r := entity.TestReminder(t)
+	r.TaskID = task.TaskID
+	s.Reminder().Create(r)
+
+	err := s.Reminder().Delete(r)
+	assert.NoError(t, err)
+
+	reminders, err := s.Reminder().FindByTask(task.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
+func TestReminderRepository_FindDue(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "reminders")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.Done = true
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+
+	// the deadline of the tasks is 2026-11-01 12:00 UTC
+	r1 := entity.TestReminder(t)
+	r1.TaskID = t1.TaskID
+	r2 := &entity.Reminder{TaskID: t1.TaskID, RemindAt: &entity.TimeISO{Time: time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)}}
+	r3 := entity.TestReminder(t)
+	r3.TaskID = t2.TaskID
+	s.Reminder().Create(r1)
+	s.Reminder().Create(r2)
+	s.Reminder().Create(r3)
+
+	now := time.Date(2026, 11, 1, 11, 30, 0, 0, time.UTC)
+	reminders, err := s.Reminder().FindDue(now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, r1.ReminderID, reminders[0].ReminderID)
+	assert.Equal(t, u.UserID, reminders[0].UserID)
+
+	reminders[0].SentAt = &entity.TimeISO{Time: now}
+	assert.NoError(t, s.Reminder().MarkSent(reminders[0]))
+
+	reminders, err = s.Reminder().FindDue(now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, r2.ReminderID, reminders[0].ReminderID)
+
+	reminders[0].Attempt(now.Add(time.Hour))
+	assert.NoError(t, s.Reminder().MarkAttempted(reminders[0]))
+
+	reminders, err = s.Reminder().FindDue(now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	reminders, err = s.Reminder().FindDue(now.Add(2*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, 1, reminders[0].Attempts)
+
+	reminders, err = s.Reminder().FindDue(now.Add(2*time.Hour), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+}
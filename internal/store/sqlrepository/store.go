@@ -0,0 +1,52 @@
+package sqlrepository
+
+import (
+	"database/sql"
+
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+// Querier is implemented by both *sql.DB and *sql.Tx,
+// so the repositories work the same way inside a transaction.
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// NewStore builds the store on top of the database,
+// its transactions run on stores built on top of *sql.Tx.
+func NewStore(db *sql.DB) *store.AppStore {
+	return newStore(db).WithTransaction(func(fn func(store.Store) error) error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(newStore(tx)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func newStore(q Querier) *store.AppStore {
+	return store.NewAppStore(
+		NewUserRepository(q),
+		NewListRepository(q),
+		NewTaskRepository(q),
+		NewItemRepository(q),
+		NewDependencyRepository(q),
+		NewLabelRepository(q),
+		NewViewRepository(q),
+		NewTemplateRepository(q),
+		NewReminderRepository(q),
+		NewPasswordResetRepository(q),
+		NewEmailVerificationRepository(q),
+		NewMFARepository(q),
+		NewRecoveryCodeRepository(q),
+		NewAPIKeyRepository(q),
+		NewLoginAttemptRepository(q),
+	)
+}
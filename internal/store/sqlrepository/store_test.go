@@ -0,0 +1,40 @@
+package sqlrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Transaction(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestList(t)
+	l.UserID = u.UserID
+	err := s.Transaction(func(tx store.Store) error {
+		if err := tx.List().Create(l); err != nil {
+			return err
+		}
+		return tx.List().Create(&entity.List{ListTitle: "", UserID: u.UserID})
+	})
+	assert.Error(t, err)
+
+	_, err = s.List().FindByID(l.ListID, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	err = s.Transaction(func(tx store.Store) error {
+		return tx.List().Create(l)
+	})
+	assert.NoError(t, err)
+
+	_, err = s.List().FindByID(l.ListID, u.UserID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,309 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/lib/pq"
+)
+
+type TaskRepository struct {
+	db Querier
+}
+
+func NewTaskRepository(db Querier) *TaskRepository {
+	return &TaskRepository{
+		db: db,
+	}
+}
+
+func (r *TaskRepository) Create(t *entity.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	deadline, dueDate := deadlineValues(t)
+
+	return r.db.QueryRow(
+		"INSERT INTO tasks (task_title, details, deadline, due_date, done, priority, estimate, list_id, position) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING task_id",
+		t.TaskTitle,
+		t.Details,
+		deadline,
+		dueDate,
+		t.Done,
+		t.Priority,
+		t.Estimate,
+		t.ListID,
+		t.Position,
+	).Scan(&t.TaskID)
+}
+
+func (r *TaskRepository) FindByID(id int) (*entity.Task, error) {
+	t := &entity.Task{}
+	var deadline, dueDate sql.NullTime
+	if err := r.db.QueryRow(
+		"SELECT task_id, task_title, details, deadline, due_date, done, priority, estimate, list_id, position FROM tasks WHERE task_id = $1",
+		id,
+	).Scan(
+		&t.TaskID,
+		&t.TaskTitle,
+		&t.Details,
+		&deadline,
+		&dueDate,
+		&t.Done,
+		&t.Priority,
+		&t.Estimate,
+		&t.ListID,
+		&t.Position,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	setDeadlines(t, deadline, dueDate)
+	return t, nil
+}
+
+func (r *TaskRepository) Edit(t *entity.Task) (*entity.Task, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	deadline, dueDate := deadlineValues(t)
+
+	_, err := r.db.Exec(
+		"UPDATE tasks SET task_title = $1, details = $2, deadline = $3, due_date = $4, done = $5, priority = $6, estimate = $7 WHERE task_id = $8",
+		t.TaskTitle,
+		t.Details,
+		deadline,
+		dueDate,
+		t.Done,
+		t.Priority,
+		t.Estimate,
+		t.TaskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TaskRepository) Delete(t *entity.Task) error {
+	_, err := r.db.Exec(
+		"DELETE FROM tasks WHERE task_id = $1",
+		t.TaskID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *TaskRepository) FindByList(listID int) ([]*entity.Task, error) {
+	tasks := make([]*entity.Task, 0)
+
+	rows, err := r.db.Query(
+		"SELECT task_id, task_title, details, deadline, due_date, done, priority, estimate, list_id, position FROM tasks WHERE list_id = $1 ORDER BY position, task_id",
+		listID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &entity.Task{}
+		var deadline, dueDate sql.NullTime
+
+		err := rows.Scan(&t.TaskID, &t.TaskTitle, &t.Details, &deadline, &dueDate, &t.Done, &t.Priority, &t.Estimate, &t.ListID, &t.Position)
+		if err != nil {
+			return nil, err
+		}
+
+		setDeadlines(t, deadline, dueDate)
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// FindByFilter builds the WHERE clause from the set fields of the filter,
+// label conditions are checked with subqueries on task_labels.
+// All day tasks are due at the end of their day in the filter location.
+func (r *TaskRepository) FindByFilter(f *entity.TaskFilter) ([]*entity.Task, error) {
+	tasks := make([]*entity.Task, 0)
+
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	due := ""
+	dueAt := func() string {
+		if due == "" {
+			loc := "UTC"
+			if f.Location != nil {
+				loc = f.Location.String()
+			}
+			due = fmt.Sprintf("COALESCE(t.deadline, (t.due_date + 1)::timestamp AT TIME ZONE %s)", arg(loc))
+		}
+		return due
+	}
+
+	if f.ListID != 0 {
+		conditions = append(conditions, "t.list_id = "+arg(f.ListID))
+	}
+
+	if f.UserID != 0 {
+		conditions = append(conditions, "t.list_id IN (SELECT list_id FROM lists WHERE user_id = "+arg(f.UserID)+")")
+	}
+
+	if f.Done != nil {
+		conditions = append(conditions, "t.done = "+arg(*f.Done))
+	}
+
+	if f.MinPriority != entity.PriorityNone {
+		conditions = append(conditions, "t.priority >= "+arg(f.MinPriority))
+	}
+
+	if f.Overdue {
+		conditions = append(conditions, "NOT t.done AND "+dueAt()+" < "+arg(f.Now))
+	}
+
+	if f.DueAfter != nil {
+		conditions = append(conditions, dueAt()+" > "+arg(*f.DueAfter))
+	}
+
+	if f.DueBefore != nil {
+		conditions = append(conditions, dueAt()+" <= "+arg(*f.DueBefore))
+	}
+
+	if labelIDs := uniqueInts(f.LabelIDs); len(labelIDs) > 0 {
+		if f.AllLabels {
+			conditions = append(conditions, fmt.Sprintf(
+				"(SELECT COUNT(*) FROM task_labels tl WHERE tl.task_id = t.task_id AND tl.label_id = ANY(%s)) = %d",
+				arg(pq.Array(labelIDs)),
+				len(labelIDs),
+			))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = t.task_id AND tl.label_id = ANY(%s))",
+				arg(pq.Array(labelIDs)),
+			))
+		}
+	}
+
+	order := "t.position, t.task_id"
+	if f.Sort == entity.TaskSortPriority {
+		order = "t.priority DESC, " + dueAt() + ", " + order
+	}
+
+	rows, err := r.db.Query(
+		`SELECT t.task_id, t.task_title, t.details, t.deadline, t.due_date, t.done, t.priority, t.estimate, t.list_id, t.position
+		FROM tasks t WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY `+order,
+		args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &entity.Task{}
+		var deadline, dueDate sql.NullTime
+
+		err := rows.Scan(&t.TaskID, &t.TaskTitle, &t.Details, &deadline, &dueDate, &t.Done, &t.Priority, &t.Estimate, &t.ListID, &t.Position)
+		if err != nil {
+			return nil, err
+		}
+
+		setDeadlines(t, deadline, dueDate)
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) Move(t *entity.Task) error {
+	_, err := r.db.Exec(
+		"UPDATE tasks SET list_id = $1, position = $2 WHERE task_id = $3",
+		t.ListID,
+		t.Position,
+		t.TaskID,
+	)
+	return err
+}
+
+// RemainingEstimates sums the estimates of the open tasks in each of the lists.
+func (r *TaskRepository) RemainingEstimates(listIDs ...int) (map[int]int, error) {
+	estimates := make(map[int]int)
+
+	rows, err := r.db.Query(
+		"SELECT list_id, SUM(estimate) FROM tasks WHERE list_id = ANY($1) AND NOT done AND estimate IS NOT NULL GROUP BY list_id",
+		pq.Array(listIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var listID, estimate int
+		if err := rows.Scan(&listID, &estimate); err != nil {
+			return nil, err
+		}
+		estimates[listID] = estimate
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return estimates, nil
+}
+
+func deadlineValues(t *entity.Task) (deadline, dueDate sql.NullTime) {
+	if t.Deadline != nil {
+		deadline = sql.NullTime{Time: t.Deadline.Time, Valid: true}
+	}
+	if t.DueDate != nil {
+		dueDate = sql.NullTime{Time: t.DueDate.Time, Valid: true}
+	}
+	return deadline, dueDate
+}
+
+func setDeadlines(t *entity.Task, deadline, dueDate sql.NullTime) {
+	if deadline.Valid {
+		t.Deadline = &entity.TimeISO{Time: deadline.Time}
+	}
+	if dueDate.Valid {
+		t.DueDate = &entity.Date{Time: dueDate.Time}
+	}
+}
+
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	unique := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,379 @@
+package sqlrepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/sqlrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRepository_Create(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+
+	err := s.Task().Create(task)
+	assert.NoError(t, err)
+	assert.NotZero(t, task.TaskID)
+}
+
+func TestTaskRepository_FindByID(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+
+	_, err := s.Task().FindByID(t1.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Task().Create(t1)
+	t2, err := s.Task().FindByID(t1.TaskID)
+	assert.NoError(t, err)
+	assert.NotNil(t, t2)
+}
+
+func TestTaskRepository_Edit(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	s.Task().Create(t1)
+
+	t1.TaskTitle = "test task 2"
+	t1.Done = true
+	t2, err := s.Task().Edit(t1)
+	assert.NoError(t, err)
+	assert.NotNil(t, t2)
+}
+
+func TestTaskRepository_Delete(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+
+	err := s.Task().Delete(task)
+	assert.NoError(t, err)
+
+	_, err = s.Task().FindByID(task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestTaskRepository_FindByList(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+
+	tasks, err := s.Task().FindByList(l.ListID)
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+	tasks, err = s.Task().FindByList(l.ListID)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+func TestTaskRepository_FindByFilter(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "labels", "task_labels")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	lb1 := entity.TestLabel(t)
+	lb2 := entity.TestLabel(t)
+	lb2.LabelTitle = "later"
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+	lb1.UserID = u.UserID
+	lb2.UserID = u.UserID
+	s.Label().Create(lb1)
+	s.Label().Create(lb2)
+	s.Label().Attach(t1.TaskID, lb1.LabelID)
+	s.Label().Attach(t1.TaskID, lb2.LabelID)
+	s.Label().Attach(t2.TaskID, lb2.LabelID)
+
+	testCases := []struct {
+		name  string
+		f     *entity.TaskFilter
+		count int
+	}{
+		{
+			name:  "list only",
+			f:     &entity.TaskFilter{ListID: l.ListID},
+			count: 2,
+		},
+		{
+			name:  "any label",
+			f:     &entity.TaskFilter{ListID: l.ListID, LabelIDs: []int{lb1.LabelID, lb2.LabelID}},
+			count: 2,
+		},
+		{
+			name:  "all labels",
+			f:     &entity.TaskFilter{ListID: l.ListID, LabelIDs: []int{lb1.LabelID, lb2.LabelID}, AllLabels: true},
+			count: 1,
+		},
+		{
+			name:  "another list",
+			f:     &entity.TaskFilter{ListID: l.ListID + 1},
+			count: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tasks, err := s.Task().FindByFilter(tc.f)
+			assert.NoError(t, err)
+			assert.Len(t, tasks, tc.count)
+		})
+	}
+}
+
+func TestTaskRepository_FindByFilter_Priority(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.Priority = entity.PriorityHigh
+	t3 := entity.TestTask(t)
+	t3.TaskTitle = "test task 3"
+	t3.Priority = entity.PriorityHigh
+	t3.Deadline = &entity.TimeISO{Time: t1.Deadline.Time.AddDate(0, 0, -1)}
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	t3.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+	s.Task().Create(t3)
+
+	tasks, err := s.Task().FindByFilter(&entity.TaskFilter{ListID: l.ListID, Sort: entity.TaskSortPriority})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, t3.TaskID, tasks[0].TaskID)
+	assert.Equal(t, t2.TaskID, tasks[1].TaskID)
+	assert.Equal(t, t1.TaskID, tasks[2].TaskID)
+}
+
+func TestTaskRepository_RemainingEstimates(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t3 := entity.TestTask(t)
+	t3.TaskTitle = "test task 3"
+	e1, e2 := 30, 45
+	t1.Estimate = &e1
+	t2.Estimate = &e2
+	t2.Done = true
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	t3.ListID = l.ListID
+	s.Task().Create(t1)
+	s.Task().Create(t2)
+	s.Task().Create(t3)
+
+	estimates, err := s.Task().RemainingEstimates(l.ListID, l.ListID+1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, estimates[l.ListID])
+	assert.Equal(t, 0, estimates[l.ListID+1])
+}
+
+func TestTaskRepository_FindByFilter_Query(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	u2 := entity.TestUser(t)
+	u2.Email = "user2@example.org"
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	s.User().Create(u1)
+	s.User().Create(u2)
+	l1.UserID = u1.UserID
+	l2.UserID = u2.UserID
+	s.List().Create(l1)
+	s.List().Create(l2)
+
+	now := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	overdue := entity.TestTask(t)
+	overdue.Deadline = &entity.TimeISO{Time: now.Add(-time.Hour)}
+	overdue.Priority = entity.PriorityUrgent
+	today := entity.TestTask(t)
+	today.TaskTitle = "test task 2"
+	today.Deadline = nil
+	today.DueDate = &entity.Date{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+	done := entity.TestTask(t)
+	done.TaskTitle = "test task 3"
+	done.Deadline = &entity.TimeISO{Time: now.AddDate(0, 0, 3)}
+	done.Done = true
+	other := entity.TestTask(t)
+	overdue.ListID = l1.ListID
+	today.ListID = l1.ListID
+	done.ListID = l1.ListID
+	other.ListID = l2.ListID
+	s.Task().Create(overdue)
+	s.Task().Create(today)
+	s.Task().Create(done)
+	s.Task().Create(other)
+
+	yes, no := true, false
+	from, to := 0, 0
+	week := 7
+
+	testCases := []struct {
+		name     string
+		f        *entity.TaskFilter
+		expected []int
+	}{
+		{
+			name:     "user",
+			f:        &entity.TaskFilter{},
+			expected: []int{overdue.TaskID, today.TaskID, done.TaskID},
+		},
+		{
+			name:     "done",
+			f:        &entity.TaskFilter{Done: &yes},
+			expected: []int{done.TaskID},
+		},
+		{
+			name:     "open",
+			f:        &entity.TaskFilter{Done: &no},
+			expected: []int{overdue.TaskID, today.TaskID},
+		},
+		{
+			name:     "overdue",
+			f:        &entity.TaskFilter{Overdue: true},
+			expected: []int{overdue.TaskID},
+		},
+		{
+			name:     "min priority",
+			f:        &entity.TaskFilter{MinPriority: entity.PriorityHigh},
+			expected: []int{overdue.TaskID},
+		},
+		{
+			name:     "today",
+			f:        &entity.TaskFilter{DueFromDays: &from, DueToDays: &to},
+			expected: []int{overdue.TaskID, today.TaskID},
+		},
+		{
+			name:     "this week",
+			f:        &entity.TaskFilter{DueFromDays: &from, DueToDays: &week, Done: &yes},
+			expected: []int{done.TaskID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.f.UserID = u1.UserID
+			tc.f.Resolve(now, time.UTC)
+
+			tasks, err := s.Task().FindByFilter(tc.f)
+			assert.NoError(t, err)
+
+			ids := make([]int, 0)
+			for _, task := range tasks {
+				ids = append(ids, task.TaskID)
+			}
+			assert.ElementsMatch(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestTaskRepository_Move(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	task := entity.TestTask(t)
+	s.User().Create(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+	s.List().Create(l1)
+	s.List().Create(l2)
+	task.ListID = l1.ListID
+	task.Position = "i"
+	s.Task().Create(task)
+
+	err := s.Task().Move(&entity.Task{TaskID: task.TaskID, ListID: l2.ListID, Position: "r"})
+	assert.NoError(t, err)
+
+	moved, err := s.Task().FindByID(task.TaskID)
+	assert.NoError(t, err)
+	assert.Equal(t, l2.ListID, moved.ListID)
+	assert.Equal(t, "r", moved.Position)
+}
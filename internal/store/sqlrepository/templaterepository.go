@@ -0,0 +1,105 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type TemplateRepository struct {
+	db Querier
+}
+
+func NewTemplateRepository(db Querier) *TemplateRepository {
+	return &TemplateRepository{
+		db: db,
+	}
+}
+
+func (r *TemplateRepository) Create(t *entity.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	tasks, err := json.Marshal(t.Tasks)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO templates (template_title, tasks, user_id) VALUES ($1, $2, $3) RETURNING template_id",
+		t.TemplateTitle,
+		tasks,
+		t.UserID,
+	).Scan(&t.TemplateID)
+}
+
+func (r *TemplateRepository) FindByID(templateID, userID int) (*entity.Template, error) {
+	t := &entity.Template{}
+	var tasks []byte
+	if err := r.db.QueryRow(
+		"SELECT template_id, template_title, tasks, user_id FROM templates WHERE template_id = $1 AND user_id = $2",
+		templateID,
+		userID,
+	).Scan(
+		&t.TemplateID,
+		&t.TemplateTitle,
+		&tasks,
+		&t.UserID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(tasks, &t.Tasks); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TemplateRepository) Delete(t *entity.Template) error {
+	_, err := r.db.Exec(
+		"DELETE FROM templates WHERE template_id = $1",
+		t.TemplateID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *TemplateRepository) FindByUser(userID int) ([]*entity.Template, error) {
+	templates := make([]*entity.Template, 0)
+
+	rows, err := r.db.Query(
+		"SELECT template_id, template_title, tasks FROM templates WHERE user_id = $1 ORDER BY template_title",
+		userID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &entity.Template{UserID: userID}
+		var tasks []byte
+
+		if err := rows.Scan(&t.TemplateID, &t.TemplateTitle, &tasks); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(tasks, &t.Tasks); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
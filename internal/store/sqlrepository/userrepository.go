@@ -2,16 +2,17 @@ package sqlrepository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
 	"github.com/AnatoliyBr/todo-app/internal/store"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db Querier) *UserRepository {
 	return &UserRepository{
 		db: db,
 	}
@@ -27,44 +28,224 @@ func (r *UserRepository) Create(u *entity.User) error {
 	}
 
 	return r.db.QueryRow(
-		"INSERT INTO users (email, encrypted_password) VALUES ($1, $2) RETURNING user_id",
+		"INSERT INTO users (email, encrypted_password, timezone, role) VALUES ($1, $2, $3, $4) RETURNING user_id",
 		u.Email,
 		u.EncryptedPassword,
+		u.Timezone,
+		u.Role,
 	).Scan(&u.UserID)
 }
 
+const userColumns = "user_id, email, encrypted_password, timezone, token_version, email_verified_at, role, disabled_at"
+
 func (r *UserRepository) FindByID(id int) (*entity.User, error) {
+	return r.findOne("SELECT "+userColumns+" FROM users WHERE user_id = $1", id)
+}
+
+func (r *UserRepository) FindByEmail(email string) (*entity.User, error) {
+	return r.findOne("SELECT "+userColumns+" FROM users WHERE email = $1", email)
+}
+
+// FindAll returns the users in the order they signed up.
+func (r *UserRepository) FindAll() ([]*entity.User, error) {
+	users := make([]*entity.User, 0)
+
+	rows, err := r.db.Query("SELECT " + userColumns + " FROM users ORDER BY user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) findOne(query string, arg interface{}) (*entity.User, error) {
+	u, err := scanUser(r.db.QueryRow(query, arg))
+	if err == sql.ErrNoRows {
+		return nil, store.ErrRecordNotFound
+	}
+	return u, err
+}
+
+func scanUser(row scanner) (*entity.User, error) {
 	u := &entity.User{}
-	if err := r.db.QueryRow(
-		"SELECT user_id, email, encrypted_password FROM users WHERE user_id = $1",
-		id,
-	).Scan(
+	var verifiedAt, disabledAt sql.NullTime
+	if err := row.Scan(
 		&u.UserID,
 		&u.Email,
 		&u.EncryptedPassword,
+		&u.Timezone,
+		&u.TokenVersion,
+		&verifiedAt,
+		&u.Role,
+		&disabledAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if verifiedAt.Valid {
+		u.EmailVerifiedAt = &entity.TimeISO{Time: verifiedAt.Time}
+	}
+	if disabledAt.Valid {
+		u.DisabledAt = &entity.TimeISO{Time: disabledAt.Time}
+	}
+	return u, nil
+}
+
+func (r *UserRepository) EditTimezone(u *entity.User) (*entity.User, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec(
+		"UPDATE users SET timezone = $1 WHERE user_id = $2",
+		u.Timezone,
+		u.UserID,
 	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, store.ErrRecordNotFound
-		}
 		return nil, err
 	}
 	return u, nil
 }
 
-func (r *UserRepository) FindByEmail(email string) (*entity.User, error) {
-	u := &entity.User{}
+// EditPassword stores the new password of the user and revokes
+// the tokens issued before by bumping the token version.
+func (r *UserRepository) EditPassword(u *entity.User) error {
+	changed := *u
+	changed.EncryptedPassword = ""
+	if err := changed.Validate(); err != nil {
+		return err
+	}
+
+	if err := changed.BeforeCreate(); err != nil {
+		return err
+	}
+
 	if err := r.db.QueryRow(
-		"SELECT user_id, email, encrypted_password FROM users WHERE email = $1",
-		email,
-	).Scan(
-		&u.UserID,
-		&u.Email,
-		&u.EncryptedPassword,
+		"UPDATE users SET encrypted_password = $1, token_version = token_version + 1 WHERE user_id = $2 RETURNING token_version",
+		changed.EncryptedPassword,
+		u.UserID,
+	).Scan(&u.TokenVersion); err != nil {
+		return err
+	}
+
+	u.EncryptedPassword = changed.EncryptedPassword
+	return nil
+}
+
+// EditPasswordHash stores the password of the user hashed again,
+// the tokens stay valid as the password is the same.
+func (r *UserRepository) EditPasswordHash(u *entity.User) error {
+	if _, err := r.db.Exec(
+		"UPDATE users SET encrypted_password = $1 WHERE user_id = $2",
+		u.EncryptedPassword,
+		u.UserID,
 	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Verify marks the email of the user as confirmed.
+func (r *UserRepository) Verify(u *entity.User) error {
+	var verifiedAt time.Time
+	if err := r.db.QueryRow(
+		"UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE user_id = $1 RETURNING email_verified_at",
+		u.UserID,
+	).Scan(&verifiedAt); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, store.ErrRecordNotFound
+			return store.ErrRecordNotFound
 		}
-		return nil, err
+		return err
 	}
-	return u, nil
+
+	u.EmailVerifiedAt = &entity.TimeISO{Time: verifiedAt}
+	return nil
+}
+
+// EditEmail stores the new email of the user, which has to be verified again.
+func (r *UserRepository) EditEmail(u *entity.User) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(
+		"UPDATE users SET email = $1, email_verified_at = NULL WHERE user_id = $2",
+		u.Email,
+		u.UserID,
+	); err != nil {
+		return err
+	}
+
+	u.EmailVerifiedAt = nil
+	return nil
+}
+
+// Delete removes the user, the data of the user is deleted by cascade.
+func (r *UserRepository) Delete(u *entity.User) error {
+	_, err := r.db.Exec(
+		"DELETE FROM users WHERE user_id = $1",
+		u.UserID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Disable disables the account of the user
+// and revokes the tokens issued before.
+func (r *UserRepository) Disable(u *entity.User) error {
+	var disabledAt time.Time
+	if err := r.db.QueryRow(
+		"UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()), token_version = token_version + 1 WHERE user_id = $1 RETURNING disabled_at, token_version",
+		u.UserID,
+	).Scan(&disabledAt, &u.TokenVersion); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	u.DisabledAt = &entity.TimeISO{Time: disabledAt}
+	return nil
+}
+
+func (r *UserRepository) Enable(u *entity.User) error {
+	if err := r.db.QueryRow(
+		"UPDATE users SET disabled_at = NULL WHERE user_id = $1 RETURNING user_id",
+		u.UserID,
+	).Scan(&u.UserID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	u.DisabledAt = nil
+	return nil
+}
+
+// RevokeTokens revokes the tokens issued to the user before
+// by bumping the token version.
+func (r *UserRepository) RevokeTokens(u *entity.User) error {
+	if err := r.db.QueryRow(
+		"UPDATE users SET token_version = token_version + 1 WHERE user_id = $1 RETURNING token_version",
+		u.UserID,
+	).Scan(&u.TokenVersion); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
 }
@@ -13,9 +13,7 @@ func TestUserRepository_Create(t *testing.T) {
 	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
 	defer teardown("users")
 
-	ur := sqlrepository.NewUserRepository(db)
-	lr := sqlrepository.NewListRepository(db)
-	s := store.NewAppStore(ur, lr)
+	s := sqlrepository.TestStore(t, db)
 	u := entity.TestUser(t)
 
 	assert.NotNil(t, u)
@@ -26,9 +24,7 @@ func TestUserRepository_FindByID(t *testing.T) {
 	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
 	defer teardown("users")
 
-	ur := sqlrepository.NewUserRepository(db)
-	lr := sqlrepository.NewListRepository(db)
-	s := store.NewAppStore(ur, lr)
+	s := sqlrepository.TestStore(t, db)
 	u1 := entity.TestUser(t)
 	_, err := s.User().FindByID(u1.UserID)
 	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
@@ -43,9 +39,7 @@ func TestUserRepository_FindByEmail(t *testing.T) {
 	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
 	defer teardown("users")
 
-	ur := sqlrepository.NewUserRepository(db)
-	lr := sqlrepository.NewListRepository(db)
-	s := store.NewAppStore(ur, lr)
+	s := sqlrepository.TestStore(t, db)
 	u1 := entity.TestUser(t)
 	_, err := s.User().FindByEmail(u1.Email)
 	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
@@ -55,3 +49,222 @@ func TestUserRepository_FindByEmail(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, u2)
 }
+
+func TestUserRepository_EditTimezone(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+
+	u1.Timezone = "Europe/Moscow"
+	_, err := s.User().EditTimezone(u1)
+	assert.NoError(t, err)
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", u2.Timezone)
+
+	u1.Timezone = "invalid"
+	_, err = s.User().EditTimezone(u1)
+	assert.Error(t, err)
+}
+
+func TestUserRepository_EditPassword(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+
+	u1.Password = "short"
+	assert.Error(t, s.User().EditPassword(u1))
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.True(t, u2.ComparePassword("password"))
+
+	u1.Password = "new password"
+	assert.NoError(t, s.User().EditPassword(u1))
+	assert.Equal(t, 1, u1.TokenVersion)
+
+	u2, err = s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.True(t, u2.ComparePassword("new password"))
+	assert.Equal(t, 1, u2.TokenVersion)
+}
+
+func TestUserRepository_Verify(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+	assert.False(t, u1.Verified())
+
+	assert.NoError(t, s.User().Verify(u1))
+	assert.True(t, u1.Verified())
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.True(t, u2.Verified())
+}
+
+func TestUserRepository_EditEmail(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+	s.User().Verify(u1)
+
+	changed := *u1
+	changed.Email = "invalid"
+	assert.Error(t, s.User().EditEmail(&changed))
+
+	changed.Email = "new@example.org"
+	assert.NoError(t, s.User().EditEmail(&changed))
+	assert.False(t, changed.Verified())
+
+	u2, err := s.User().FindByEmail("new@example.org")
+	assert.NoError(t, err)
+	assert.Equal(t, u1.UserID, u2.UserID)
+	assert.False(t, u2.Verified())
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users", "lists", "tasks", "items", "reminders", "labels", "task_labels")
+
+	s := sqlrepository.TestStore(t, db)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestList(t)
+	l.UserID = u.UserID
+	s.List().Create(l)
+
+	task := entity.TestTask(t)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+
+	i := entity.TestItem(t)
+	i.TaskID = task.TaskID
+	s.Item().Create(i)
+
+	rm := entity.TestReminder(t)
+	rm.TaskID = task.TaskID
+	s.Reminder().Create(rm)
+
+	lb := entity.TestLabel(t)
+	lb.UserID = u.UserID
+	s.Label().Create(lb)
+	s.Label().Attach(task.TaskID, lb.LabelID)
+
+	assert.NoError(t, s.User().Delete(u))
+
+	_, err := s.User().FindByID(u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	lists, err := s.List().FindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Empty(t, lists)
+
+	_, err = s.Task().FindByID(task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	items, err := s.Item().FindByTask(task.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	reminders, err := s.Reminder().FindByTask(task.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	labels, err := s.Label().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+}
+
+func TestUserRepository_EditPasswordHash(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+
+	changed := *u1
+	assert.NoError(t, changed.Rehash("password"))
+	assert.NoError(t, s.User().EditPasswordHash(&changed))
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, changed.EncryptedPassword, u2.EncryptedPassword)
+	assert.Equal(t, 0, u2.TokenVersion)
+}
+
+func TestUserRepository_FindAll(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+	u2 := entity.TestUser(t)
+	u2.Email = "other@example.org"
+	s.User().Create(u2)
+
+	users, err := s.User().FindAll()
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, u1.UserID, users[0].UserID)
+	assert.Equal(t, entity.RoleUser, users[0].Role)
+}
+
+func TestUserRepository_Disable(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+
+	assert.NoError(t, s.User().Disable(u1))
+	assert.True(t, u1.Disabled())
+	assert.Equal(t, 1, u1.TokenVersion)
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.True(t, u2.Disabled())
+
+	assert.NoError(t, s.User().Enable(u1))
+	assert.False(t, u1.Disabled())
+
+	u2, err = s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.False(t, u2.Disabled())
+	assert.Equal(t, 1, u2.TokenVersion)
+
+	assert.EqualError(t, s.User().Disable(&entity.User{UserID: u1.UserID + 1}), store.ErrRecordNotFound.Error())
+}
+
+func TestUserRepository_RevokeTokens(t *testing.T) {
+	db, teardown := sqlrepository.TestDB(t, testDatabaseURL)
+	defer teardown("users")
+
+	s := sqlrepository.TestStore(t, db)
+	u1 := entity.TestUser(t)
+	s.User().Create(u1)
+
+	assert.NoError(t, s.User().RevokeTokens(u1))
+	assert.Equal(t, 1, u1.TokenVersion)
+
+	u2, err := s.User().FindByID(u1.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, u2.TokenVersion)
+}
@@ -0,0 +1,127 @@
+package sqlrepository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type ViewRepository struct {
+	db Querier
+}
+
+func NewViewRepository(db Querier) *ViewRepository {
+	return &ViewRepository{
+		db: db,
+	}
+}
+
+func (r *ViewRepository) Create(v *entity.View) error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+
+	filter, err := json.Marshal(v.Filter)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO views (view_title, filter, user_id) VALUES ($1, $2, $3) RETURNING view_id",
+		v.ViewTitle,
+		filter,
+		v.UserID,
+	).Scan(&v.ViewID)
+}
+
+func (r *ViewRepository) FindByID(viewID, userID int) (*entity.View, error) {
+	v := &entity.View{}
+	var filter []byte
+	if err := r.db.QueryRow(
+		"SELECT view_id, view_title, filter, user_id FROM views WHERE view_id = $1 AND user_id = $2",
+		viewID,
+		userID,
+	).Scan(
+		&v.ViewID,
+		&v.ViewTitle,
+		&filter,
+		&v.UserID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(filter, &v.Filter); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *ViewRepository) Edit(v *entity.View) (*entity.View, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	filter, err := json.Marshal(v.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.Exec(
+		"UPDATE views SET view_title = $1, filter = $2 WHERE view_id = $3",
+		v.ViewTitle,
+		filter,
+		v.ViewID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *ViewRepository) Delete(v *entity.View) error {
+	_, err := r.db.Exec(
+		"DELETE FROM views WHERE view_id = $1",
+		v.ViewID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ViewRepository) FindByUser(userID int) ([]*entity.View, error) {
+	views := make([]*entity.View, 0)
+
+	rows, err := r.db.Query(
+		"SELECT view_id, view_title, filter FROM views WHERE user_id = $1 ORDER BY view_title",
+		userID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v := &entity.View{UserID: userID}
+		var filter []byte
+
+		if err := rows.Scan(&v.ViewID, &v.ViewTitle, &filter); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(filter, &v.Filter); err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
@@ -1,17 +1,51 @@
 package store
 
 type AppStore struct {
-	userRepository UserRepository
-	listRepository ListRepository
+	userRepository              UserRepository
+	listRepository              ListRepository
+	taskRepository              TaskRepository
+	itemRepository              ItemRepository
+	dependencyRepository        DependencyRepository
+	labelRepository             LabelRepository
+	viewRepository              ViewRepository
+	templateRepository          TemplateRepository
+	reminderRepository          ReminderRepository
+	passwordResetRepository     PasswordResetRepository
+	emailVerificationRepository EmailVerificationRepository
+	mfaRepository               MFARepository
+	recoveryCodeRepository      RecoveryCodeRepository
+	apiKeyRepository            APIKeyRepository
+	loginAttemptRepository      LoginAttemptRepository
+	transaction                 func(func(Store) error) error
 }
 
-func NewAppStore(ur UserRepository, lr ListRepository) *AppStore {
+func NewAppStore(ur UserRepository, lr ListRepository, tr TaskRepository, ir ItemRepository, dr DependencyRepository, lbr LabelRepository, vr ViewRepository, tpr TemplateRepository, rr ReminderRepository, prr PasswordResetRepository, evr EmailVerificationRepository, mr MFARepository, rcr RecoveryCodeRepository, akr APIKeyRepository, lar LoginAttemptRepository) *AppStore {
 	return &AppStore{
-		userRepository: ur,
-		listRepository: lr,
+		userRepository:              ur,
+		listRepository:              lr,
+		taskRepository:              tr,
+		itemRepository:              ir,
+		dependencyRepository:        dr,
+		labelRepository:             lbr,
+		viewRepository:              vr,
+		templateRepository:          tpr,
+		reminderRepository:          rr,
+		passwordResetRepository:     prr,
+		emailVerificationRepository: evr,
+		mfaRepository:               mr,
+		recoveryCodeRepository:      rcr,
+		apiKeyRepository:            akr,
+		loginAttemptRepository:      lar,
 	}
 }
 
+// WithTransaction sets how the store runs transactions,
+// without it Transaction calls fn on the store itself.
+func (s *AppStore) WithTransaction(transaction func(func(Store) error) error) *AppStore {
+	s.transaction = transaction
+	return s
+}
+
 func (s *AppStore) User() UserRepository {
 	return s.userRepository
 }
@@ -19,3 +53,64 @@ func (s *AppStore) User() UserRepository {
 func (s *AppStore) List() ListRepository {
 	return s.listRepository
 }
+
+func (s *AppStore) Task() TaskRepository {
+	return s.taskRepository
+}
+
+func (s *AppStore) Item() ItemRepository {
+	return s.itemRepository
+}
+
+func (s *AppStore) Dependency() DependencyRepository {
+	return s.dependencyRepository
+}
+
+func (s *AppStore) Label() LabelRepository {
+	return s.labelRepository
+}
+
+func (s *AppStore) View() ViewRepository {
+	return s.viewRepository
+}
+
+func (s *AppStore) Template() TemplateRepository {
+	return s.templateRepository
+}
+
+func (s *AppStore) Reminder() ReminderRepository {
+	return s.reminderRepository
+}
+
+func (s *AppStore) PasswordReset() PasswordResetRepository {
+	return s.passwordResetRepository
+}
+
+func (s *AppStore) EmailVerification() EmailVerificationRepository {
+	return s.emailVerificationRepository
+}
+
+func (s *AppStore) MFA() MFARepository {
+	return s.mfaRepository
+}
+
+func (s *AppStore) RecoveryCode() RecoveryCodeRepository {
+	return s.recoveryCodeRepository
+}
+
+func (s *AppStore) APIKey() APIKeyRepository {
+	return s.apiKeyRepository
+}
+
+func (s *AppStore) LoginAttempt() LoginAttemptRepository {
+	return s.loginAttemptRepository
+}
+
+// Transaction calls fn with a store whose changes are kept
+// only if fn succeeds.
+func (s *AppStore) Transaction(fn func(Store) error) error {
+	if s.transaction == nil {
+		return fn(s)
+	}
+	return s.transaction(fn)
+}
@@ -0,0 +1,86 @@
+package testrepository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type APIKeyRepository struct {
+	keys map[int]*entity.APIKey
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[int]*entity.APIKey),
+	}
+}
+
+func (r *APIKeyRepository) Create(k *entity.APIKey) error {
+	if err := k.Validate(); err != nil {
+		return err
+	}
+
+	k.APIKeyID = len(r.keys) + 1
+	r.keys[k.APIKeyID] = k
+
+	return nil
+}
+
+func (r *APIKeyRepository) FindByID(apiKeyID, userID int) (*entity.APIKey, error) {
+	k, ok := r.keys[apiKeyID]
+	if !ok || k.UserID != userID {
+		return nil, store.ErrRecordNotFound
+	}
+	return k, nil
+}
+
+func (r *APIKeyRepository) FindByHash(keyHash string) (*entity.APIKey, error) {
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (r *APIKeyRepository) Delete(k *entity.APIKey) error {
+	if _, ok := r.keys[k.APIKeyID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.keys, k.APIKeyID)
+	return nil
+}
+
+func (r *APIKeyRepository) FindByUser(userID int) ([]*entity.APIKey, error) {
+	keys := make([]*entity.APIKey, 0)
+
+	for _, k := range r.keys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].APIKeyID < keys[j].APIKeyID
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Touch(k *entity.APIKey) error {
+	stored, ok := r.keys[k.APIKeyID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	if stored.LastUsedAt == nil || stored.LastUsedAt.Before(now.Add(-time.Minute)) {
+		stored.LastUsedAt = &entity.TimeISO{Time: now}
+	}
+	k.LastUsedAt = stored.LastUsedAt
+	return nil
+}
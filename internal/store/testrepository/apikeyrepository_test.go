@@ -0,0 +1,122 @@
+package testrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	k.Generate()
+	assert.NoError(t, s.APIKey().Create(k))
+	assert.NotNil(t, k.APIKeyID)
+
+	invalid := entity.TestAPIKey(t)
+	invalid.UserID = u.UserID
+	invalid.Scope = "admin"
+	assert.Error(t, s.APIKey().Create(invalid))
+}
+
+func TestAPIKeyRepository_FindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	k.Generate()
+	_, err := s.APIKey().FindByID(1, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.APIKey().Create(k)
+	found, err := s.APIKey().FindByID(k.APIKeyID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, k.Prefix, found.Prefix)
+
+	_, err = s.APIKey().FindByID(k.APIKeyID, u.UserID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAPIKeyRepository_FindByHash(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	key, _ := k.Generate()
+	_, err := s.APIKey().FindByHash(entity.HashToken(key))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.APIKey().Create(k)
+	found, err := s.APIKey().FindByHash(entity.HashToken(key))
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, found.UserID)
+	assert.Equal(t, entity.ScopeRead, found.Scope)
+}
+
+func TestAPIKeyRepository_Delete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	k.Generate()
+	s.APIKey().Create(k)
+	assert.NoError(t, s.APIKey().Delete(k))
+
+	_, err := s.APIKey().FindByID(k.APIKeyID, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAPIKeyRepository_FindByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	for _, name := range []string{"ci", "backup"} {
+		k := entity.TestAPIKey(t)
+		k.Name = name
+		k.UserID = u.UserID
+		k.Generate()
+		s.APIKey().Create(k)
+	}
+
+	keys, err := s.APIKey().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "ci", keys[0].Name)
+
+	keys, err = s.APIKey().FindByUser(u.UserID + 1)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestAPIKeyRepository_Touch(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	k.Generate()
+	s.APIKey().Create(k)
+
+	assert.NoError(t, s.APIKey().Touch(k))
+	assert.NotNil(t, k.LastUsedAt)
+	usedAt := k.LastUsedAt.Time
+
+	assert.NoError(t, s.APIKey().Touch(k))
+	found, _ := s.APIKey().FindByID(k.APIKeyID, u.UserID)
+	assert.True(t, usedAt.Equal(found.LastUsedAt.Time))
+}
@@ -0,0 +1,109 @@
+package testrepository
+
+// userCascade removes what a deleted user owns from the repositories,
+// as ON DELETE CASCADE does in the SQL store.
+type userCascade struct {
+	lists         *ListRepository
+	tasks         *TaskRepository
+	items         *ItemRepository
+	dependencies  *DependencyRepository
+	labels        *LabelRepository
+	views         *ViewRepository
+	templates     *TemplateRepository
+	reminders     *ReminderRepository
+	resets        *PasswordResetRepository
+	verifications *EmailVerificationRepository
+	mfas          *MFARepository
+	recoveryCodes *RecoveryCodeRepository
+	apiKeys       *APIKeyRepository
+	loginAttempts *LoginAttemptRepository
+}
+
+func (c *userCascade) delete(userID int) {
+	taskIDs := make(map[int]bool)
+	for id, t := range c.tasks.tasks {
+		if l, ok := c.lists.lists[t.ListID]; ok && l.UserID == userID {
+			taskIDs[id] = true
+		}
+	}
+
+	for id, i := range c.items.items {
+		if taskIDs[i.TaskID] {
+			delete(c.items.items, id)
+		}
+	}
+
+	for d := range c.dependencies.dependencies {
+		if taskIDs[d.TaskID] || taskIDs[d.BlockerID] {
+			delete(c.dependencies.dependencies, d)
+		}
+	}
+
+	for id, rm := range c.reminders.reminders {
+		if taskIDs[rm.TaskID] {
+			delete(c.reminders.reminders, id)
+		}
+	}
+
+	for id := range taskIDs {
+		delete(c.labels.taskLabels, id)
+		delete(c.tasks.tasks, id)
+	}
+
+	for id, l := range c.lists.lists {
+		if l.UserID == userID {
+			delete(c.lists.lists, id)
+		}
+	}
+
+	for id, lb := range c.labels.labels {
+		if lb.UserID == userID {
+			delete(c.labels.labels, id)
+		}
+	}
+
+	for id, v := range c.views.views {
+		if v.UserID == userID {
+			delete(c.views.views, id)
+		}
+	}
+
+	for id, t := range c.templates.templates {
+		if t.UserID == userID {
+			delete(c.templates.templates, id)
+		}
+	}
+
+	for id, p := range c.resets.resets {
+		if p.UserID == userID {
+			delete(c.resets.resets, id)
+		}
+	}
+
+	for id, v := range c.verifications.verifications {
+		if v.UserID == userID {
+			delete(c.verifications.verifications, id)
+		}
+	}
+
+	delete(c.mfas.mfas, userID)
+
+	for id, rc := range c.recoveryCodes.codes {
+		if rc.UserID == userID {
+			delete(c.recoveryCodes.codes, id)
+		}
+	}
+
+	for id, k := range c.apiKeys.keys {
+		if k.UserID == userID {
+			delete(c.apiKeys.keys, id)
+		}
+	}
+
+	// the attempts are kept for auditing, as with ON DELETE SET NULL
+	for _, a := range c.loginAttempts.attempts {
+		if a.UserID != nil && *a.UserID == userID {
+			a.UserID = nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package testrepository
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type DependencyRepository struct {
+	dependencies map[entity.Dependency]bool
+	tasks        *TaskRepository
+}
+
+// NewDependencyRepository needs the task repository to know which blockers are done.
+func NewDependencyRepository(tr *TaskRepository) *DependencyRepository {
+	return &DependencyRepository{
+		dependencies: make(map[entity.Dependency]bool),
+		tasks:        tr,
+	}
+}
+
+func (r *DependencyRepository) Create(d *entity.Dependency) error {
+	if r.dependencies[*d] {
+		return errors.New("dependency has already exist")
+	}
+
+	r.dependencies[*d] = true
+	return nil
+}
+
+func (r *DependencyRepository) Delete(d *entity.Dependency) error {
+	if !r.dependencies[*d] {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.dependencies, *d)
+	return nil
+}
+
+func (r *DependencyRepository) FindBlockers(taskID int) ([]int, error) {
+	blockers := make([]int, 0)
+
+	for d := range r.dependencies {
+		if d.TaskID == taskID {
+			blockers = append(blockers, d.BlockerID)
+		}
+	}
+
+	sort.Ints(blockers)
+	return blockers, nil
+}
+
+func (r *DependencyRepository) OpenBlockers(taskIDs ...int) (map[int][]int, error) {
+	blockers := make(map[int][]int)
+
+	for _, id := range taskIDs {
+		ids, _ := r.FindBlockers(id)
+		for _, blockerID := range ids {
+			if b, ok := r.tasks.tasks[blockerID]; ok && !b.Done {
+				blockers[id] = append(blockers[id], blockerID)
+			}
+		}
+	}
+
+	return blockers, nil
+}
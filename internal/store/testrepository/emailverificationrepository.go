@@ -0,0 +1,57 @@
+package testrepository
+
+import (
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type EmailVerificationRepository struct {
+	verifications map[int]*entity.EmailVerification
+	lastID        int
+}
+
+func NewEmailVerificationRepository() *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		verifications: make(map[int]*entity.EmailVerification),
+	}
+}
+
+func (r *EmailVerificationRepository) Create(v *entity.EmailVerification) error {
+	r.lastID++
+	v.VerificationID = r.lastID
+	r.verifications[v.VerificationID] = v
+
+	return nil
+}
+
+func (r *EmailVerificationRepository) FindByToken(tokenHash string) (*entity.EmailVerification, error) {
+	for _, v := range r.verifications {
+		if v.TokenHash == tokenHash {
+			return v, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (r *EmailVerificationRepository) FindLastByUser(userID int) (*entity.EmailVerification, error) {
+	var last *entity.EmailVerification
+	for _, v := range r.verifications {
+		if v.UserID == userID && (last == nil || v.VerificationID > last.VerificationID) {
+			last = v
+		}
+	}
+
+	if last == nil {
+		return nil, store.ErrRecordNotFound
+	}
+	return last, nil
+}
+
+func (r *EmailVerificationRepository) DeleteByUser(userID int) error {
+	for id, v := range r.verifications {
+		if v.UserID == userID {
+			delete(r.verifications, id)
+		}
+	}
+	return nil
+}
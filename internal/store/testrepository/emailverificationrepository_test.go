@@ -0,0 +1,68 @@
+package testrepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerificationRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v, _, _ := entity.NewEmailVerification(u.UserID, time.Hour)
+	assert.NoError(t, s.EmailVerification().Create(v))
+	assert.NotNil(t, v.VerificationID)
+}
+
+func TestEmailVerificationRepository_FindByToken(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v, token, _ := entity.NewEmailVerification(u.UserID, time.Hour)
+	_, err := s.EmailVerification().FindByToken(entity.HashToken(token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.EmailVerification().Create(v)
+	found, err := s.EmailVerification().FindByToken(entity.HashToken(token))
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, found.UserID)
+}
+
+func TestEmailVerificationRepository_FindLastByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	_, err := s.EmailVerification().FindLastByUser(u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	v1, _, _ := entity.NewEmailVerification(u.UserID, time.Hour)
+	s.EmailVerification().Create(v1)
+	v2, _, _ := entity.NewEmailVerification(u.UserID, time.Hour)
+	s.EmailVerification().Create(v2)
+
+	last, err := s.EmailVerification().FindLastByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, v2.VerificationID, last.VerificationID)
+}
+
+func TestEmailVerificationRepository_DeleteByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v, token, _ := entity.NewEmailVerification(u.UserID, time.Hour)
+	s.EmailVerification().Create(v)
+
+	assert.NoError(t, s.EmailVerification().DeleteByUser(u.UserID))
+
+	_, err := s.EmailVerification().FindByToken(entity.HashToken(token))
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
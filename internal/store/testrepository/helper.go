@@ -0,0 +1,52 @@
+package testrepository
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+func TestStore(t *testing.T) *store.AppStore {
+	t.Helper()
+
+	ur := NewUserRepository()
+	lr := NewListRepository()
+	lbr := NewLabelRepository()
+	tr := NewTaskRepository(lr, lbr)
+
+	c := &userCascade{
+		lists:         lr,
+		tasks:         tr,
+		items:         NewItemRepository(),
+		dependencies:  NewDependencyRepository(tr),
+		labels:        lbr,
+		views:         NewViewRepository(),
+		templates:     NewTemplateRepository(),
+		reminders:     NewReminderRepository(tr, ur),
+		resets:        NewPasswordResetRepository(),
+		verifications: NewEmailVerificationRepository(),
+		mfas:          NewMFARepository(),
+		recoveryCodes: NewRecoveryCodeRepository(),
+		apiKeys:       NewAPIKeyRepository(),
+		loginAttempts: NewLoginAttemptRepository(),
+	}
+	ur.cascade = c
+
+	return store.NewAppStore(
+		ur,
+		lr,
+		tr,
+		c.items,
+		c.dependencies,
+		lbr,
+		c.views,
+		c.templates,
+		c.reminders,
+		c.resets,
+		c.verifications,
+		c.mfas,
+		c.recoveryCodes,
+		c.apiKeys,
+		c.loginAttempts,
+	)
+}
@@ -0,0 +1,108 @@
+package testrepository
+
+import (
+	"sort"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type ItemRepository struct {
+	items map[int]*entity.Item
+}
+
+func NewItemRepository() *ItemRepository {
+	return &ItemRepository{
+		items: make(map[int]*entity.Item),
+	}
+}
+
+func (r *ItemRepository) Create(i *entity.Item) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	i.Position = 1
+	for _, item := range r.items {
+		if item.TaskID == i.TaskID && item.Position >= i.Position {
+			i.Position = item.Position + 1
+		}
+	}
+
+	i.ItemID = len(r.items) + 1
+	r.items[i.ItemID] = i
+
+	return nil
+}
+
+func (r *ItemRepository) FindByID(itemID, taskID int) (*entity.Item, error) {
+	i, ok := r.items[itemID]
+	if !ok || i.TaskID != taskID {
+		return nil, store.ErrRecordNotFound
+	}
+	return i, nil
+}
+
+func (r *ItemRepository) Edit(i *entity.Item) (*entity.Item, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.items[i.ItemID] = i
+	return i, nil
+}
+
+func (r *ItemRepository) Delete(i *entity.Item) error {
+	if _, ok := r.items[i.ItemID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.items, i.ItemID)
+	return nil
+}
+
+func (r *ItemRepository) FindByTask(taskID int) ([]*entity.Item, error) {
+	items := make([]*entity.Item, 0)
+
+	for _, i := range r.items {
+		if i.TaskID == taskID {
+			items = append(items, i)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+
+	return items, nil
+}
+
+func (r *ItemRepository) Reorder(taskID int, itemIDs []int) error {
+	for pos, id := range itemIDs {
+		if i, ok := r.items[id]; ok && i.TaskID == taskID {
+			i.Position = pos + 1
+		}
+	}
+	return nil
+}
+
+func (r *ItemRepository) Progress(taskIDs ...int) (map[int]entity.Progress, error) {
+	progress := make(map[int]entity.Progress, len(taskIDs))
+
+	for _, id := range taskIDs {
+		for _, i := range r.items {
+			if i.TaskID != id {
+				continue
+			}
+
+			p := progress[id]
+			p.Total++
+			if i.Done {
+				p.Done++
+			}
+			progress[id] = p
+		}
+	}
+
+	return progress, nil
+}
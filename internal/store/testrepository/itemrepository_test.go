@@ -0,0 +1,143 @@
+package testrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	i2 := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i1.TaskID = task.TaskID
+	i2.TaskID = task.TaskID
+
+	assert.NoError(t, s.Item().Create(i1))
+	assert.NoError(t, s.Item().Create(i2))
+	assert.Equal(t, 1, i1.Position)
+	assert.Equal(t, 2, i2.Position)
+}
+
+func TestItemRepository_FindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i1.TaskID = task.TaskID
+
+	_, err := s.Item().FindByID(i1.ItemID, task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Item().Create(i1)
+	i2, err := s.Item().FindByID(i1.ItemID, task.TaskID)
+	assert.NoError(t, err)
+	assert.NotNil(t, i2)
+}
+
+func TestItemRepository_Edit(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i1.TaskID = task.TaskID
+	s.Item().Create(i1)
+
+	i1.Done = true
+	i2, err := s.Item().Edit(i1)
+	assert.NoError(t, err)
+	assert.True(t, i2.Done)
+}
+
+func TestItemRepository_Delete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i.TaskID = task.TaskID
+	s.Item().Create(i)
+
+	err := s.Item().Delete(i)
+	assert.NoError(t, err)
+
+	_, err = s.Item().FindByID(i.ItemID, task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestItemRepository_Reorder(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	i2 := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i1.TaskID = task.TaskID
+	i2.TaskID = task.TaskID
+	s.Item().Create(i1)
+	s.Item().Create(i2)
+
+	err := s.Item().Reorder(task.TaskID, []int{i2.ItemID, i1.ItemID})
+	assert.NoError(t, err)
+
+	items, err := s.Item().FindByTask(task.TaskID)
+	assert.NoError(t, err)
+	assert.Equal(t, i2.ItemID, items[0].ItemID)
+	assert.Equal(t, i1.ItemID, items[1].ItemID)
+}
+
+func TestItemRepository_Progress(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	i1 := entity.TestItem(t)
+	i2 := entity.TestItem(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	i1.TaskID = task.TaskID
+	i1.Done = true
+	i2.TaskID = task.TaskID
+	s.Item().Create(i1)
+	s.Item().Create(i2)
+
+	progress, err := s.Item().Progress(task.TaskID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Progress{Done: 1, Total: 2}, progress[task.TaskID])
+}
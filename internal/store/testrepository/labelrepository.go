@@ -0,0 +1,129 @@
+package testrepository
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type LabelRepository struct {
+	labels     map[int]*entity.Label
+	taskLabels map[int]map[int]bool
+}
+
+func NewLabelRepository() *LabelRepository {
+	return &LabelRepository{
+		labels:     make(map[int]*entity.Label),
+		taskLabels: make(map[int]map[int]bool),
+	}
+}
+
+func (r *LabelRepository) Create(l *entity.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.checkTitle(l); err != nil {
+		return err
+	}
+
+	l.LabelID = len(r.labels) + 1
+	r.labels[l.LabelID] = l
+
+	return nil
+}
+
+func (r *LabelRepository) FindByID(labelID, userID int) (*entity.Label, error) {
+	l, ok := r.labels[labelID]
+	if !ok || l.UserID != userID {
+		return nil, store.ErrRecordNotFound
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) Edit(l *entity.Label) (*entity.Label, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkTitle(l); err != nil {
+		return nil, err
+	}
+
+	r.labels[l.LabelID] = l
+	return l, nil
+}
+
+func (r *LabelRepository) Delete(l *entity.Label) error {
+	if _, ok := r.labels[l.LabelID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	for _, labels := range r.taskLabels {
+		delete(labels, l.LabelID)
+	}
+
+	delete(r.labels, l.LabelID)
+	return nil
+}
+
+func (r *LabelRepository) FindByUser(userID int) ([]*entity.Label, error) {
+	labels := make([]*entity.Label, 0)
+
+	for _, l := range r.labels {
+		if l.UserID == userID {
+			labels = append(labels, l)
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].LabelTitle < labels[j].LabelTitle
+	})
+
+	return labels, nil
+}
+
+func (r *LabelRepository) Attach(taskID, labelID int) error {
+	if _, ok := r.taskLabels[taskID]; !ok {
+		r.taskLabels[taskID] = make(map[int]bool)
+	}
+
+	r.taskLabels[taskID][labelID] = true
+	return nil
+}
+
+func (r *LabelRepository) Detach(taskID, labelID int) error {
+	if !r.taskLabels[taskID][labelID] {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.taskLabels[taskID], labelID)
+	return nil
+}
+
+func (r *LabelRepository) FindByTasks(taskIDs ...int) (map[int][]*entity.Label, error) {
+	labels := make(map[int][]*entity.Label)
+
+	for _, id := range taskIDs {
+		for labelID := range r.taskLabels[id] {
+			labels[id] = append(labels[id], r.labels[labelID])
+		}
+
+		sort.Slice(labels[id], func(i, j int) bool {
+			return labels[id][i].LabelTitle < labels[id][j].LabelTitle
+		})
+	}
+
+	return labels, nil
+}
+
+func (r *LabelRepository) checkTitle(l *entity.Label) error {
+	for _, label := range r.labels {
+		if label.LabelID != l.LabelID && label.UserID == l.UserID && label.LabelTitle == l.LabelTitle {
+			return errors.New("another label with this title has already exist")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package testrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	assert.NoError(t, s.Label().Create(l))
+	assert.NotNil(t, l.LabelID)
+
+	dup := entity.TestLabel(t)
+	dup.UserID = u.UserID
+	assert.Error(t, s.Label().Create(dup))
+}
+
+func TestLabelRepository_FindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	_, err := s.Label().FindByID(1, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Label().Create(l)
+	found, err := s.Label().FindByID(l.LabelID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, l.LabelTitle, found.LabelTitle)
+
+	_, err = s.Label().FindByID(l.LabelID, u.UserID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestLabelRepository_Edit(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	s.Label().Create(l)
+
+	l.LabelTitle = "later"
+	l.Color = "#00FF00"
+	edited, err := s.Label().Edit(l)
+	assert.NoError(t, err)
+	assert.Equal(t, "later", edited.LabelTitle)
+	assert.Equal(t, "#00ff00", edited.Color)
+}
+
+func TestLabelRepository_Delete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	assert.EqualError(t, s.Label().Delete(l), store.ErrRecordNotFound.Error())
+
+	s.Label().Create(l)
+	assert.NoError(t, s.Label().Delete(l))
+
+	_, err := s.Label().FindByID(l.LabelID, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestLabelRepository_FindByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	labels, err := s.Label().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	s.Label().Create(l)
+	labels, err = s.Label().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+}
+
+func TestLabelRepository_AttachDetach(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	lb := entity.TestLabel(t)
+	s.User().Create(u)
+	l.UserID = u.UserID
+	s.List().Create(l)
+	task.ListID = l.ListID
+	s.Task().Create(task)
+	lb.UserID = u.UserID
+	s.Label().Create(lb)
+
+	assert.EqualError(t, s.Label().Detach(task.TaskID, lb.LabelID), store.ErrRecordNotFound.Error())
+
+	assert.NoError(t, s.Label().Attach(task.TaskID, lb.LabelID))
+	labels, err := s.Label().FindByTasks(task.TaskID)
+	assert.NoError(t, err)
+	assert.Len(t, labels[task.TaskID], 1)
+	assert.Equal(t, lb.LabelID, labels[task.TaskID][0].LabelID)
+
+	assert.NoError(t, s.Label().Detach(task.TaskID, lb.LabelID))
+	labels, err = s.Label().FindByTasks(task.TaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, labels[task.TaskID])
+}
@@ -2,6 +2,7 @@ package testrepository
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
 	"github.com/AnatoliyBr/todo-app/internal/store"
@@ -22,6 +23,12 @@ func (r *ListRepository) Create(l *entity.List) error {
 		return err
 	}
 
+	for _, list := range r.lists {
+		if list.UserID == l.UserID && list.NormalizedTitle() == l.NormalizedTitle() {
+			return errors.New("another list with this title has already exist")
+		}
+	}
+
 	l.ListID = len(r.lists) + 1
 	r.lists[l.ListID] = l
 
@@ -43,11 +50,16 @@ func (r *ListRepository) Edit(l *entity.List) (*entity.List, error) {
 	}
 
 	for _, list := range r.lists {
-		if list.UserID == l.UserID && list.ListTitle == l.ListTitle {
+		if list.ListID != l.ListID && list.UserID == l.UserID && list.NormalizedTitle() == l.NormalizedTitle() {
 			return nil, errors.New("another list with this title has already exist")
 		}
 	}
 
+	if list, ok := r.lists[l.ListID]; ok {
+		l.Position = list.Position
+		l.ArchivedAt = list.ArchivedAt
+	}
+
 	r.lists[l.ListID] = l
 	return l, nil
 }
@@ -61,18 +73,49 @@ func (r *ListRepository) Delete(l *entity.List) error {
 	return nil
 }
 
-func (r *ListRepository) FindByUser(userID int) ([]*entity.List, error) {
+func (r *ListRepository) FindByUser(userID int, archived bool) ([]*entity.List, error) {
 	lists := make([]*entity.List, 0)
 
 	for _, l := range r.lists {
-		if l.UserID == userID {
+		if l.UserID == userID && l.Archived() == archived {
 			lists = append(lists, l)
 		}
 	}
 
-	if len(lists) > 0 {
-		return lists, nil
-	} else {
-		return nil, store.ErrRecordNotFound
+	sort.Slice(lists, func(i, j int) bool {
+		if lists[i].Position != lists[j].Position {
+			return lists[i].Position < lists[j].Position
+		}
+		return lists[i].ListID < lists[j].ListID
+	})
+
+	return lists, nil
+}
+
+func (r *ListRepository) Move(l *entity.List) error {
+	list, ok := r.lists[l.ListID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	list.Position = l.Position
+	return nil
+}
+
+func (r *ListRepository) Archive(l *entity.List) error {
+	list, ok := r.lists[l.ListID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	list.ArchivedAt = l.ArchivedAt
+	return nil
+}
+
+func (r *ListRepository) IsArchived(listID int) (bool, error) {
+	list, ok := r.lists[listID]
+	if !ok {
+		return false, store.ErrRecordNotFound
 	}
+	return list.Archived(), nil
 }
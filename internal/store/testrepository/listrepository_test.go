@@ -2,6 +2,7 @@ package testrepository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
 	"github.com/AnatoliyBr/todo-app/internal/store"
@@ -10,9 +11,7 @@ import (
 )
 
 func TestListRepository_Create(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
 	l := entity.TestList(t)
 	s.User().Create(u)
@@ -20,12 +19,26 @@ func TestListRepository_Create(t *testing.T) {
 
 	err := s.List().Create(l)
 	assert.NoError(t, err)
+
+	dup := entity.TestList(t)
+	dup.ListTitle = "Test Title 1"
+	dup.UserID = u.UserID
+	err = s.List().Create(dup)
+	assert.Error(t, err)
+
+	cyrillic := entity.TestList(t)
+	cyrillic.ListTitle = "Список покупок"
+	cyrillic.UserID = u.UserID
+	err = s.List().Create(cyrillic)
+	assert.NoError(t, err)
+
+	found, err := s.List().FindByID(cyrillic.ListID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Список покупок", found.ListTitle)
 }
 
 func TestListRepository_FindByID(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	s.User().Create(u)
@@ -41,9 +54,7 @@ func TestListRepository_FindByID(t *testing.T) {
 }
 
 func TestListRepository_Edit(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	l2 := entity.TestList(t)
@@ -56,12 +67,18 @@ func TestListRepository_Edit(t *testing.T) {
 	l3, err := s.List().Edit(l2)
 	assert.NoError(t, err)
 	assert.NotNil(t, l3)
+
+	l1.ListTitle = "Test Title 1"
+	_, err = s.List().Edit(l1)
+	assert.NoError(t, err)
+
+	l1.ListTitle = "test title 2"
+	_, err = s.List().Edit(l1)
+	assert.Error(t, err)
 }
 
 func TestListRepository_Delete(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
 	l := entity.TestList(t)
 	s.User().Create(u)
@@ -76,9 +93,7 @@ func TestListRepository_Delete(t *testing.T) {
 }
 
 func TestListRepository_FindByUser(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	l2 := entity.TestList(t)
@@ -87,12 +102,79 @@ func TestListRepository_FindByUser(t *testing.T) {
 	l1.UserID = u.UserID
 	l2.UserID = u.UserID
 
-	_, err := s.List().FindByUser(u.UserID)
-	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+	lists, err := s.List().FindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Empty(t, lists)
+
+	s.List().Create(l1)
+	s.List().Create(l2)
+	lists, err = s.List().FindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 2)
+}
+
+func TestListRepository_Move(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	s.User().Create(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+	l1.Position = "i"
+	l2.Position = "r"
+	s.List().Create(l1)
+	s.List().Create(l2)
 
+	l2.Position = "a"
+	err := s.List().Move(l2)
+	assert.NoError(t, err)
+
+	lists, err := s.List().FindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, l2.ListID, lists[0].ListID)
+}
+
+func TestListRepository_Archive(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	s.User().Create(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
 	s.List().Create(l1)
 	s.List().Create(l2)
-	lists, err := s.List().FindByUser(u.UserID)
+
+	l1.ArchivedAt = &entity.TimeISO{Time: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	err := s.List().Archive(l1)
 	assert.NoError(t, err)
-	assert.NotNil(t, lists)
+
+	archived, err := s.List().IsArchived(l1.ListID)
+	assert.NoError(t, err)
+	assert.True(t, archived)
+
+	lists, err := s.List().FindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+	assert.Equal(t, l2.ListID, lists[0].ListID)
+
+	lists, err = s.List().FindByUser(u.UserID, true)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+	assert.Equal(t, l1.ListID, lists[0].ListID)
+	assert.NotNil(t, lists[0].ArchivedAt)
+
+	l1.ArchivedAt = nil
+	err = s.List().Archive(l1)
+	assert.NoError(t, err)
+
+	archived, err = s.List().IsArchived(l1.ListID)
+	assert.NoError(t, err)
+	assert.False(t, archived)
+
+	_, err = s.List().IsArchived(l1.ListID + l2.ListID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
 }
@@ -0,0 +1,74 @@
+package testrepository
+
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+)
+
+type LoginAttemptRepository struct {
+	attempts []*entity.LoginAttempt
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{}
+}
+
+func (r *LoginAttemptRepository) Create(a *entity.LoginAttempt) error {
+	a.AttemptID = len(r.attempts) + 1
+	r.attempts = append(r.attempts, a)
+
+	return nil
+}
+
+// FailuresByEmail counts the failures since the last successful login.
+func (r *LoginAttemptRepository) FailuresByEmail(email string, since time.Time) (*entity.LoginFailures, error) {
+	f := &entity.LoginFailures{}
+	for _, a := range r.attempts {
+		if a.Email != email || !a.CreatedAt.After(since) {
+			continue
+		}
+
+		if a.Succeeded {
+			f = &entity.LoginFailures{}
+			continue
+		}
+
+		f.Count++
+		f.Last = a.CreatedAt
+	}
+	return f, nil
+}
+
+// MFAFailuresByUser counts the failed second factor checks since the last
+// successful one. A correct password doesn't reset them.
+func (r *LoginAttemptRepository) MFAFailuresByUser(userID int, since time.Time) (*entity.LoginFailures, error) {
+	f := &entity.LoginFailures{}
+	for _, a := range r.attempts {
+		if !a.MFA || a.UserID == nil || *a.UserID != userID || !a.CreatedAt.After(since) {
+			continue
+		}
+
+		if a.Succeeded {
+			f = &entity.LoginFailures{}
+			continue
+		}
+
+		f.Count++
+		f.Last = a.CreatedAt
+	}
+	return f, nil
+}
+
+// FailuresByIP counts the failures whether or not a login has succeeded
+// since, so a client can't reset them by logging in to its own account.
+func (r *LoginAttemptRepository) FailuresByIP(ip string, since time.Time) (*entity.LoginFailures, error) {
+	f := &entity.LoginFailures{}
+	for _, a := range r.attempts {
+		if a.IP == ip && !a.Succeeded && a.CreatedAt.After(since) {
+			f.Count++
+			f.Last = a.CreatedAt
+		}
+	}
+	return f, nil
+}
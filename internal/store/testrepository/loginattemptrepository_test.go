@@ -0,0 +1,76 @@
+package testrepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+
+	a := &entity.LoginAttempt{Email: "user@example.org", IP: "127.0.0.1", CreatedAt: time.Now()}
+	assert.NoError(t, s.LoginAttempt().Create(a))
+	assert.NotNil(t, a.AttemptID)
+}
+
+func TestLoginAttemptRepository_Failures(t *testing.T) {
+	s := testrepository.TestStore(t)
+	now := time.Now().Truncate(time.Second)
+
+	attempts := []*entity.LoginAttempt{
+		{Email: "user@example.org", IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{Email: "user@example.org", IP: "10.0.0.1", CreatedAt: now.Add(-time.Minute * 3)},
+		{Email: "user@example.org", IP: "10.0.0.1", Succeeded: true, CreatedAt: now.Add(-time.Minute * 2)},
+		{Email: "user@example.org", IP: "10.0.0.1", CreatedAt: now.Add(-time.Minute)},
+		{Email: "other@example.org", IP: "10.0.0.1", CreatedAt: now},
+	}
+	for _, a := range attempts {
+		s.LoginAttempt().Create(a)
+	}
+
+	f, err := s.LoginAttempt().FailuresByEmail("user@example.org", now.Add(-time.Minute*30))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.Count)
+	assert.True(t, now.Add(-time.Minute).Equal(f.Last))
+
+	f, err = s.LoginAttempt().FailuresByIP("10.0.0.1", now.Add(-time.Minute*30))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, f.Count)
+	assert.True(t, now.Equal(f.Last))
+
+	f, err = s.LoginAttempt().FailuresByIP("10.0.0.2", now.Add(-time.Minute*30))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.Count)
+}
+
+func TestLoginAttemptRepository_MFAFailuresByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+	now := time.Now().Truncate(time.Second)
+
+	attempts := []*entity.LoginAttempt{
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, MFA: true, CreatedAt: now.Add(-time.Minute * 4)},
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, MFA: true, Succeeded: true, CreatedAt: now.Add(-time.Minute * 3)},
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, MFA: true, CreatedAt: now.Add(-time.Minute * 2)},
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, Succeeded: true, CreatedAt: now.Add(-time.Minute)},
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, MFA: true, CreatedAt: now},
+		{Email: u.Email, IP: "10.0.0.1", UserID: &u.UserID, CreatedAt: now},
+	}
+	for _, a := range attempts {
+		s.LoginAttempt().Create(a)
+	}
+
+	f, err := s.LoginAttempt().MFAFailuresByUser(u.UserID, now.Add(-time.Minute*30))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.Count)
+	assert.True(t, now.Equal(f.Last))
+
+	f, err = s.LoginAttempt().MFAFailuresByUser(u.UserID+1, now.Add(-time.Minute*30))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.Count)
+}
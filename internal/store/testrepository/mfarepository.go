@@ -0,0 +1,65 @@
+package testrepository
+
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type MFARepository struct {
+	mfas map[int]*entity.MFA
+}
+
+func NewMFARepository() *MFARepository {
+	return &MFARepository{
+		mfas: make(map[int]*entity.MFA),
+	}
+}
+
+func (r *MFARepository) Save(m *entity.MFA) error {
+	m.EnabledAt = nil
+	m.LastStep = 0
+
+	stored := *m
+	r.mfas[m.UserID] = &stored
+	return nil
+}
+
+func (r *MFARepository) FindByUser(userID int) (*entity.MFA, error) {
+	m, ok := r.mfas[userID]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	found := *m
+	return &found, nil
+}
+
+func (r *MFARepository) Enable(m *entity.MFA) error {
+	stored, ok := r.mfas[m.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	enabledAt := time.Now()
+	stored.EnabledAt = &enabledAt
+	m.EnabledAt = &enabledAt
+	return nil
+}
+
+func (r *MFARepository) UseStep(m *entity.MFA, step int64) error {
+	stored, ok := r.mfas[m.UserID]
+	if !ok || stored.LastStep >= step {
+		return store.ErrRecordNotFound
+	}
+
+	stored.LastStep = step
+	m.LastStep = step
+	return nil
+}
+
+func (r *MFARepository) Delete(m *entity.MFA) error {
+	delete(r.mfas, m.UserID)
+	return nil
+}
@@ -0,0 +1,46 @@
+package testrepository
+
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type PasswordResetRepository struct {
+	resets map[int]*entity.PasswordReset
+}
+
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{
+		resets: make(map[int]*entity.PasswordReset),
+	}
+}
+
+func (r *PasswordResetRepository) Create(p *entity.PasswordReset) error {
+	p.ResetID = len(r.resets) + 1
+	r.resets[p.ResetID] = p
+
+	return nil
+}
+
+func (r *PasswordResetRepository) FindByToken(tokenHash string) (*entity.PasswordReset, error) {
+	for _, p := range r.resets {
+		if p.TokenHash == tokenHash {
+			return p, nil
+		}
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (r *PasswordResetRepository) MarkUsed(p *entity.PasswordReset) error {
+	stored, ok := r.resets[p.ResetID]
+	if !ok || stored.UsedAt != nil {
+		return store.ErrRecordNotFound
+	}
+
+	usedAt := time.Now()
+	stored.UsedAt = &usedAt
+	p.UsedAt = &usedAt
+	return nil
+}
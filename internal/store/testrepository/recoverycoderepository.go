@@ -0,0 +1,46 @@
+package testrepository
+
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type RecoveryCodeRepository struct {
+	codes  map[int]*entity.RecoveryCode
+	lastID int
+}
+
+func NewRecoveryCodeRepository() *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		codes: make(map[int]*entity.RecoveryCode),
+	}
+}
+
+func (r *RecoveryCodeRepository) Replace(userID int, codes []*entity.RecoveryCode) error {
+	for id, c := range r.codes {
+		if c.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+
+	for _, c := range codes {
+		r.lastID++
+		c.CodeID = r.lastID
+		c.UserID = userID
+		r.codes[c.CodeID] = c
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepository) Use(userID int, codeHash string) error {
+	for _, c := range r.codes {
+		if c.UserID == userID && c.CodeHash == codeHash && c.UsedAt == nil {
+			usedAt := time.Now()
+			c.UsedAt = &usedAt
+			return nil
+		}
+	}
+	return store.ErrRecordNotFound
+}
@@ -0,0 +1,136 @@
+package testrepository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type ReminderRepository struct {
+	reminders map[int]*entity.Reminder
+	tasks     *TaskRepository
+	users     *UserRepository
+}
+
+// NewReminderRepository needs the task and user repositories
+// to find out when reminders relative to a task are due.
+func NewReminderRepository(tr *TaskRepository, ur *UserRepository) *ReminderRepository {
+	return &ReminderRepository{
+		reminders: make(map[int]*entity.Reminder),
+		tasks:     tr,
+		users:     ur,
+	}
+}
+
+func (r *ReminderRepository) Create(rm *entity.Reminder) error {
+	if err := rm.Validate(); err != nil {
+		return err
+	}
+
+	rm.ReminderID = len(r.reminders) + 1
+	r.reminders[rm.ReminderID] = rm
+
+	return nil
+}
+
+func (r *ReminderRepository) FindByID(reminderID, taskID int) (*entity.Reminder, error) {
+	rm, ok := r.reminders[reminderID]
+	if !ok || rm.TaskID != taskID {
+		return nil, store.ErrRecordNotFound
+	}
+	return rm, nil
+}
+
+func (r *ReminderRepository) Delete(rm *entity.Reminder) error {
+	if _, ok := r.reminders[rm.ReminderID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.reminders, rm.ReminderID)
+	return nil
+}
+
+func (r *ReminderRepository) FindByTask(taskID int) ([]*entity.Reminder, error) {
+	reminders := make([]*entity.Reminder, 0)
+
+	for _, rm := range r.reminders {
+		if rm.TaskID == taskID {
+			reminders = append(reminders, rm)
+		}
+	}
+
+	sortReminders(reminders)
+	return reminders, nil
+}
+
+func (r *ReminderRepository) FindDue(now time.Time, limit int) ([]*entity.Reminder, error) {
+	reminders := make([]*entity.Reminder, 0)
+
+	for _, rm := range r.reminders {
+		if rm.SentAt != nil || (rm.NextAttemptAt != nil && rm.NextAttemptAt.After(now)) {
+			continue
+		}
+
+		t, ok := r.tasks.tasks[rm.TaskID]
+		if !ok || t.Done {
+			continue
+		}
+
+		l, ok := r.tasks.lists.lists[t.ListID]
+		if !ok {
+			continue
+		}
+
+		loc := time.UTC
+		if u, ok := r.users.users[l.UserID]; ok {
+			loc = u.Location()
+		}
+
+		if rm.Time(t, loc).After(now) {
+			continue
+		}
+
+		rm.UserID = l.UserID
+		reminders = append(reminders, rm)
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		if reminders[i].Attempts != reminders[j].Attempts {
+			return reminders[i].Attempts < reminders[j].Attempts
+		}
+		return reminders[i].ReminderID < reminders[j].ReminderID
+	})
+	if len(reminders) > limit {
+		reminders = reminders[:limit]
+	}
+	return reminders, nil
+}
+
+func (r *ReminderRepository) MarkAttempted(rm *entity.Reminder) error {
+	reminder, ok := r.reminders[rm.ReminderID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	reminder.Attempts = rm.Attempts
+	reminder.NextAttemptAt = rm.NextAttemptAt
+	return nil
+}
+
+func (r *ReminderRepository) MarkSent(rm *entity.Reminder) error {
+	reminder, ok := r.reminders[rm.ReminderID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	reminder.SentAt = rm.SentAt
+	return nil
+}
+
+func sortReminders(reminders []*entity.Reminder) {
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].ReminderID < reminders[j].ReminderID
+	})
+}
@@ -0,0 +1,215 @@
+package testrepository
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type TaskRepository struct {
+	tasks  map[int]*entity.Task
+	lists  *ListRepository
+	labels *LabelRepository
+}
+
+// NewTaskRepository needs the list and label repositories
+// to filter tasks by their owner and labels.
+func NewTaskRepository(lr *ListRepository, lbr *LabelRepository) *TaskRepository {
+	return &TaskRepository{
+		tasks:  make(map[int]*entity.Task),
+		lists:  lr,
+		labels: lbr,
+	}
+}
+
+func (r *TaskRepository) Create(t *entity.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.checkTitle(t); err != nil {
+		return err
+	}
+
+	t.TaskID = len(r.tasks) + 1
+	r.tasks[t.TaskID] = t
+
+	return nil
+}
+
+func (r *TaskRepository) FindByID(id int) (*entity.Task, error) {
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *TaskRepository) Edit(t *entity.Task) (*entity.Task, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkTitle(t); err != nil {
+		return nil, err
+	}
+
+	if task, ok := r.tasks[t.TaskID]; ok {
+		t.Position = task.Position
+	}
+
+	r.tasks[t.TaskID] = t
+	return t, nil
+}
+
+func (r *TaskRepository) Delete(t *entity.Task) error {
+	if _, ok := r.tasks[t.TaskID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.tasks, t.TaskID)
+	return nil
+}
+
+func (r *TaskRepository) FindByList(listID int) ([]*entity.Task, error) {
+	tasks := make([]*entity.Task, 0)
+
+	for _, t := range r.tasks {
+		if t.ListID == listID {
+			tasks = append(tasks, t)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) FindByFilter(f *entity.TaskFilter) ([]*entity.Task, error) {
+	tasks := make([]*entity.Task, 0)
+
+	loc := time.UTC
+	if f.Location != nil {
+		loc = f.Location
+	}
+
+	for _, t := range r.tasks {
+		if f.ListID != 0 && t.ListID != f.ListID {
+			continue
+		}
+
+		if len(f.LabelIDs) > 0 && !r.hasLabels(t.TaskID, f.LabelIDs, f.AllLabels) {
+			continue
+		}
+
+		if f.UserID != 0 && !r.ownedBy(t.ListID, f.UserID) {
+			continue
+		}
+
+		if f.Done != nil && t.Done != *f.Done {
+			continue
+		}
+
+		if t.Priority < f.MinPriority {
+			continue
+		}
+
+		due := t.Due(loc)
+		if f.Overdue && (t.Done || !due.Before(f.Now)) {
+			continue
+		}
+
+		if f.DueAfter != nil && !due.After(*f.DueAfter) {
+			continue
+		}
+
+		if f.DueBefore != nil && due.After(*f.DueBefore) {
+			continue
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if f.Sort == entity.TaskSortPriority {
+			if tasks[i].Priority != tasks[j].Priority {
+				return tasks[i].Priority > tasks[j].Priority
+			}
+			if di, dj := tasks[i].Due(loc), tasks[j].Due(loc); !di.Equal(dj) {
+				return di.Before(dj)
+			}
+		}
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) Move(t *entity.Task) error {
+	task, ok := r.tasks[t.TaskID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	moved := *task
+	moved.ListID = t.ListID
+	if err := r.checkTitle(&moved); err != nil {
+		return err
+	}
+
+	task.ListID = t.ListID
+	task.Position = t.Position
+	return nil
+}
+
+func (r *TaskRepository) RemainingEstimates(listIDs ...int) (map[int]int, error) {
+	estimates := make(map[int]int)
+
+	for _, id := range listIDs {
+		for _, t := range r.tasks {
+			if t.ListID == id && !t.Done && t.Estimate != nil {
+				estimates[id] += *t.Estimate
+			}
+		}
+	}
+
+	return estimates, nil
+}
+
+func (r *TaskRepository) checkTitle(t *entity.Task) error {
+	for _, task := range r.tasks {
+		if task.TaskID != t.TaskID && task.ListID == t.ListID && task.TaskTitle == t.TaskTitle {
+			return errors.New("another task with this title has already exist")
+		}
+	}
+	return nil
+}
+
+func (r *TaskRepository) ownedBy(listID, userID int) bool {
+	l, ok := r.lists.lists[listID]
+	return ok && l.UserID == userID
+}
+
+func (r *TaskRepository) hasLabels(taskID int, labelIDs []int, all bool) bool {
+	for _, id := range labelIDs {
+		attached := r.labels.taskLabels[taskID][id]
+		if attached && !all {
+			return true
+		}
+		if !attached && all {
+			return false
+		}
+	}
+	return all
+}
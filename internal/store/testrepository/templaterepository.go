@@ -0,0 +1,69 @@
+package testrepository
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type TemplateRepository struct {
+	templates map[int]*entity.Template
+}
+
+func NewTemplateRepository() *TemplateRepository {
+	return &TemplateRepository{
+		templates: make(map[int]*entity.Template),
+	}
+}
+
+func (r *TemplateRepository) Create(t *entity.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	for _, template := range r.templates {
+		if template.UserID == t.UserID && template.TemplateTitle == t.TemplateTitle {
+			return errors.New("another template with this title has already exist")
+		}
+	}
+
+	t.TemplateID = len(r.templates) + 1
+	r.templates[t.TemplateID] = t
+
+	return nil
+}
+
+func (r *TemplateRepository) FindByID(templateID, userID int) (*entity.Template, error) {
+	t, ok := r.templates[templateID]
+	if !ok || t.UserID != userID {
+		return nil, store.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *TemplateRepository) Delete(t *entity.Template) error {
+	if _, ok := r.templates[t.TemplateID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.templates, t.TemplateID)
+	return nil
+}
+
+func (r *TemplateRepository) FindByUser(userID int) ([]*entity.Template, error) {
+	templates := make([]*entity.Template, 0)
+
+	for _, t := range r.templates {
+		if t.UserID == userID {
+			templates = append(templates, t)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].TemplateTitle < templates[j].TemplateTitle
+	})
+
+	return templates, nil
+}
@@ -0,0 +1,76 @@
+package testrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	tpl := entity.TestTemplate(t)
+	tpl.UserID = u.UserID
+	assert.NoError(t, s.Template().Create(tpl))
+	assert.NotNil(t, tpl.TemplateID)
+
+	dup := entity.TestTemplate(t)
+	dup.UserID = u.UserID
+	assert.Error(t, s.Template().Create(dup))
+}
+
+func TestTemplateRepository_FindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	tpl := entity.TestTemplate(t)
+	tpl.UserID = u.UserID
+	_, err := s.Template().FindByID(1, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.Template().Create(tpl)
+	found, err := s.Template().FindByID(tpl.TemplateID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, tpl.TemplateTitle, found.TemplateTitle)
+	assert.Len(t, found.Tasks, 2)
+	assert.Equal(t, []string{"create branch"}, found.Tasks[0].Items)
+
+	_, err = s.Template().FindByID(tpl.TemplateID, u.UserID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestTemplateRepository_Delete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	tpl := entity.TestTemplate(t)
+	tpl.UserID = u.UserID
+	assert.EqualError(t, s.Template().Delete(tpl), store.ErrRecordNotFound.Error())
+
+	s.Template().Create(tpl)
+	assert.NoError(t, s.Template().Delete(tpl))
+}
+
+func TestTemplateRepository_FindByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	templates, err := s.Template().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Empty(t, templates)
+
+	tpl := entity.TestTemplate(t)
+	tpl.UserID = u.UserID
+	s.Template().Create(tpl)
+	templates, err = s.Template().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+}
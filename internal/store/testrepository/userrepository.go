@@ -1,12 +1,17 @@
 package testrepository
 
 import (
+	"sort"
+	"time"
+
 	"github.com/AnatoliyBr/todo-app/internal/entity"
 	"github.com/AnatoliyBr/todo-app/internal/store"
 )
 
 type UserRepository struct {
-	users map[int]*entity.User
+	users   map[int]*entity.User
+	lastID  int
+	cascade *userCascade
 }
 
 func NewUserRepository() *UserRepository {
@@ -24,7 +29,8 @@ func (r *UserRepository) Create(u *entity.User) error {
 		return err
 	}
 
-	u.UserID = len(r.users) + 1
+	r.lastID++
+	u.UserID = r.lastID
 	r.users[u.UserID] = u
 
 	return nil
@@ -38,6 +44,18 @@ func (r *UserRepository) FindByID(id int) (*entity.User, error) {
 	return u, nil
 }
 
+func (r *UserRepository) FindAll() ([]*entity.User, error) {
+	users := make([]*entity.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	return users, nil
+}
+
 func (r *UserRepository) FindByEmail(email string) (*entity.User, error) {
 	for _, u := range r.users {
 		if u.Email == email {
@@ -46,3 +64,128 @@ func (r *UserRepository) FindByEmail(email string) (*entity.User, error) {
 	}
 	return nil, store.ErrRecordNotFound
 }
+
+func (r *UserRepository) EditTimezone(u *entity.User) (*entity.User, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	stored.Timezone = u.Timezone
+	return stored, nil
+}
+
+func (r *UserRepository) EditPassword(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	changed := *u
+	changed.EncryptedPassword = ""
+	if err := changed.Validate(); err != nil {
+		return err
+	}
+
+	if err := changed.BeforeCreate(); err != nil {
+		return err
+	}
+
+	stored.EncryptedPassword = changed.EncryptedPassword
+	stored.TokenVersion++
+	u.EncryptedPassword = stored.EncryptedPassword
+	u.TokenVersion = stored.TokenVersion
+	return nil
+}
+
+func (r *UserRepository) EditPasswordHash(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	stored.EncryptedPassword = u.EncryptedPassword
+	return nil
+}
+
+func (r *UserRepository) Verify(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	if stored.EmailVerifiedAt == nil {
+		stored.EmailVerifiedAt = &entity.TimeISO{Time: time.Now()}
+	}
+	u.EmailVerifiedAt = stored.EmailVerifiedAt
+	return nil
+}
+
+func (r *UserRepository) EditEmail(u *entity.User) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	stored.Email = u.Email
+	stored.EmailVerifiedAt = nil
+	u.EmailVerifiedAt = nil
+	return nil
+}
+
+func (r *UserRepository) Delete(u *entity.User) error {
+	if _, ok := r.users[u.UserID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.users, u.UserID)
+	if r.cascade != nil {
+		r.cascade.delete(u.UserID)
+	}
+	return nil
+}
+
+func (r *UserRepository) Disable(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	if stored.DisabledAt == nil {
+		stored.DisabledAt = &entity.TimeISO{Time: time.Now()}
+	}
+	stored.TokenVersion++
+	u.DisabledAt = stored.DisabledAt
+	u.TokenVersion = stored.TokenVersion
+	return nil
+}
+
+func (r *UserRepository) Enable(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	stored.DisabledAt = nil
+	u.DisabledAt = nil
+	return nil
+}
+
+func (r *UserRepository) RevokeTokens(u *entity.User) error {
+	stored, ok := r.users[u.UserID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	stored.TokenVersion++
+	u.TokenVersion = stored.TokenVersion
+	return nil
+}
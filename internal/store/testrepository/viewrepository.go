@@ -0,0 +1,89 @@
+package testrepository
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+)
+
+type ViewRepository struct {
+	views map[int]*entity.View
+}
+
+func NewViewRepository() *ViewRepository {
+	return &ViewRepository{
+		views: make(map[int]*entity.View),
+	}
+}
+
+func (r *ViewRepository) Create(v *entity.View) error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.checkTitle(v); err != nil {
+		return err
+	}
+
+	v.ViewID = len(r.views) + 1
+	r.views[v.ViewID] = v
+
+	return nil
+}
+
+func (r *ViewRepository) FindByID(viewID, userID int) (*entity.View, error) {
+	v, ok := r.views[viewID]
+	if !ok || v.UserID != userID {
+		return nil, store.ErrRecordNotFound
+	}
+	return v, nil
+}
+
+func (r *ViewRepository) Edit(v *entity.View) (*entity.View, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkTitle(v); err != nil {
+		return nil, err
+	}
+
+	r.views[v.ViewID] = v
+	return v, nil
+}
+
+func (r *ViewRepository) Delete(v *entity.View) error {
+	if _, ok := r.views[v.ViewID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.views, v.ViewID)
+	return nil
+}
+
+func (r *ViewRepository) FindByUser(userID int) ([]*entity.View, error) {
+	views := make([]*entity.View, 0)
+
+	for _, v := range r.views {
+		if v.UserID == userID {
+			views = append(views, v)
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].ViewTitle < views[j].ViewTitle
+	})
+
+	return views, nil
+}
+
+func (r *ViewRepository) checkTitle(v *entity.View) error {
+	for _, view := range r.views {
+		if view.ViewID != v.ViewID && view.UserID == v.UserID && view.ViewTitle == v.ViewTitle {
+			return errors.New("another view with this title has already exist")
+		}
+	}
+	return nil
+}
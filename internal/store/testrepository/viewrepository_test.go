@@ -0,0 +1,96 @@
+package testrepository_test
+
+import (
+	"testing"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewRepository_Create(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	assert.NoError(t, s.View().Create(v))
+	assert.NotNil(t, v.ViewID)
+
+	dup := entity.TestView(t)
+	dup.UserID = u.UserID
+	assert.Error(t, s.View().Create(dup))
+}
+
+func TestViewRepository_FindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	_, err := s.View().FindByID(1, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	s.View().Create(v)
+	found, err := s.View().FindByID(v.ViewID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, v.ViewTitle, found.ViewTitle)
+	assert.Equal(t, entity.PriorityHigh, found.Filter.MinPriority)
+
+	_, err = s.View().FindByID(v.ViewID, u.UserID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestViewRepository_Edit(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	s.View().Create(v)
+
+	done := true
+	v.ViewTitle = "done"
+	v.Filter = &entity.TaskFilter{Done: &done}
+	edited, err := s.View().Edit(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", edited.ViewTitle)
+
+	found, err := s.View().FindByID(v.ViewID, u.UserID)
+	assert.NoError(t, err)
+	assert.True(t, *found.Filter.Done)
+}
+
+func TestViewRepository_Delete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	assert.EqualError(t, s.View().Delete(v), store.ErrRecordNotFound.Error())
+
+	s.View().Create(v)
+	assert.NoError(t, s.View().Delete(v))
+}
+
+func TestViewRepository_FindByUser(t *testing.T) {
+	s := testrepository.TestStore(t)
+	u := entity.TestUser(t)
+	s.User().Create(u)
+
+	views, err := s.View().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Empty(t, views)
+
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	s.View().Create(v)
+	views, err = s.View().FindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, views, 1)
+}
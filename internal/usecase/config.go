@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HasherBcrypt   = "bcrypt"
+	HasherArgon2id = "argon2id"
+)
+
+var (
+	errUnknownHasher     = errors.New("password_hasher must be bcrypt or argon2id")
+	errInvalidBcryptCost = errors.New("bcrypt_cost must be from 4 to 31")
+	errInvalidArgon2     = errors.New("argon2_time and argon2_threads must be positive, argon2_memory at least 8 KiB per thread")
+	errNoMFAKey          = errors.New("no mfa key configured, set MFA_KEY")
+)
+
+type Config struct {
+	StrictDependencies bool          `toml:"strict_dependencies"`
+	PublicURL          string        `toml:"public_url"`
+	PasswordResetTTL   time.Duration `toml:"password_reset_ttl"`
+
+	RequireVerifiedEmail       bool          `toml:"require_verified_email"`
+	EmailVerificationTTL       time.Duration `toml:"email_verification_ttl"`
+	VerificationResendInterval time.Duration `toml:"verification_resend_interval"`
+
+	PasswordHasher string `toml:"password_hasher"`
+	BcryptCost     int    `toml:"bcrypt_cost"`
+	Argon2Memory   uint32 `toml:"argon2_memory"`
+	Argon2Time     uint32 `toml:"argon2_time"`
+	Argon2Threads  uint8  `toml:"argon2_threads"`
+
+	LoginFreeAttempts   int           `toml:"login_free_attempts"`
+	LoginIPFreeAttempts int           `toml:"login_ip_free_attempts"`
+	LoginLockout        time.Duration `toml:"login_lockout"`
+	LoginMaxLockout     time.Duration `toml:"login_max_lockout"`
+	LoginAttemptsWindow time.Duration `toml:"login_attempts_window"`
+	MFAFreeAttempts     int           `toml:"mfa_free_attempts"`
+
+	// MFAKey is the base64 encoded 32 byte key the TOTP secrets are encrypted with.
+	MFAKey    string
+	MFAIssuer string `toml:"mfa_issuer"`
+
+	Dev bool `toml:"dev"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		StrictDependencies: false,
+		PublicURL:          "http://localhost:8080",
+		PasswordResetTTL:   time.Hour,
+
+		RequireVerifiedEmail:       false,
+		EmailVerificationTTL:       24 * time.Hour,
+		VerificationResendInterval: time.Minute,
+
+		PasswordHasher: HasherBcrypt,
+		BcryptCost:     12,
+		Argon2Memory:   64 * 1024,
+		Argon2Time:     3,
+		Argon2Threads:  2,
+
+		LoginFreeAttempts:   5,
+		LoginIPFreeAttempts: 20,
+		LoginLockout:        time.Minute,
+		LoginMaxLockout:     time.Hour,
+		LoginAttemptsWindow: 24 * time.Hour,
+		MFAFreeAttempts:     3,
+
+		MFAKey:    os.Getenv("MFA_KEY"),
+		MFAIssuer: "todo-app",
+	}
+}
+
+// NewPasswordHasher builds the password hasher chosen in the config.
+func NewPasswordHasher(config *Config) (entity.PasswordHasher, error) {
+	switch config.PasswordHasher {
+	case HasherBcrypt:
+		if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+			return nil, errInvalidBcryptCost
+		}
+		return &entity.BcryptHasher{Cost: config.BcryptCost}, nil
+	case HasherArgon2id:
+		if config.Argon2Time == 0 || config.Argon2Threads == 0 || config.Argon2Memory < 8*uint32(config.Argon2Threads) {
+			return nil, errInvalidArgon2
+		}
+		return &entity.Argon2idHasher{
+			Memory:  config.Argon2Memory,
+			Time:    config.Argon2Time,
+			Threads: config.Argon2Threads,
+		}, nil
+	default:
+		return nil, errUnknownHasher
+	}
+}
+
+// NewMFAKey decodes the key from the config. In dev mode a missing key
+// is replaced with a generated one, so TOTP secrets don't outlive a restart.
+func NewMFAKey(config *Config) ([]byte, error) {
+	if config.MFAKey == "" {
+		if !config.Dev {
+			return nil, errNoMFAKey
+		}
+		return entity.GenerateMFAKey()
+	}
+	return entity.ParseMFAKey(config.MFAKey)
+}
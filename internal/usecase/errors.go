@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidItemsOrder        = errors.New("item ids must list every item of the task exactly once")
+	ErrDependencyExists         = errors.New("dependency has already exist")
+	ErrDependencyCycle          = errors.New("dependency would create a cycle")
+	ErrTaskBlocked              = errors.New("task is blocked by unfinished tasks")
+	ErrInvalidMove              = errors.New("move needs at most one of after_id and before_id from the target list")
+	ErrListArchived             = errors.New("tasks of an archived list cannot be changed")
+	ErrInvalidResetToken        = errors.New("password reset token is invalid or expired")
+	ErrNoMailer                 = errors.New("email is not configured")
+	ErrNoMFAKey                 = errors.New("two-factor authentication is not configured")
+	ErrInvalidVerificationToken = errors.New("email verification token is invalid or expired")
+	ErrEmailVerified            = errors.New("email has already been verified")
+	ErrEmailNotVerified         = errors.New("email must be verified first")
+	ErrVerificationTooSoon      = errors.New("verification email was sent recently, try again later")
+	ErrMFAEnabled               = errors.New("two-factor authentication has already been enabled")
+	ErrMFANotEnrolled           = errors.New("two-factor authentication has not been set up")
+	ErrInvalidMFACode           = errors.New("two-factor code is invalid")
+	ErrInvalidAPIKey            = errors.New("api key is invalid or expired")
+	ErrIncorrectEmailOrPassword = errors.New("incorrect email or password")
+	ErrIncorrectPassword        = errors.New("incorrect password")
+	ErrEmailTaken               = errors.New("email is used by another user")
+	ErrAccountDisabled          = errors.New("account is disabled")
+)
+
+// LoginLockedError is returned by Login while too many
+// attempts have failed for the email or the IP address.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
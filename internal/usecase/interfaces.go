@@ -1,15 +1,99 @@
 package usecase
 
-import "github.com/AnatoliyBr/todo-app/internal/entity"
+import (
+	"time"
+
+	"github.com/AnatoliyBr/todo-app/internal/entity"
+)
 
 type UseCase interface {
 	UsersCreate(*entity.User) error
 	UsersFindByID(int) (*entity.User, error)
 	UsersFindByEmail(string) (*entity.User, error)
+	UsersEditTimezone(*entity.User) (*entity.User, error)
+	UsersFindOrCreateVerified(string) (*entity.User, error)
+	Login(string, string, string) (*entity.User, error)
+	UsersEditPassword(*entity.User, string, string) error
+	UsersEditEmail(*entity.User, string, string) error
+	UsersDelete(*entity.User, string) error
+	UsersFindAll() ([]*entity.User, error)
+	UsersDisable(*entity.User) error
+	UsersEnable(*entity.User) error
+	UsersRevokeTokens(*entity.User) error
+	UsersUsage(int) (*entity.Usage, error)
+
+	PasswordResetsCreate(string) error
+	PasswordResetsConfirm(string, string) error
+
+	EmailVerificationsSend(*entity.User) error
+	EmailVerificationsConfirm(string) error
+
+	MFAEnroll(*entity.User) (string, string, error)
+	MFAConfirm(*entity.User, string) ([]string, error)
+	MFAEnabled(int) (bool, error)
+	MFAVerify(int, string) error
+	LoginMFA(*entity.User, string, string) error
+	MFADisable(*entity.User, string) error
+
+	APIKeysCreate(*entity.APIKey) (string, error)
+	APIKeysFindByID(int, int) (*entity.APIKey, error)
+	APIKeysDelete(*entity.APIKey) error
+	APIKeysFindByUser(int) ([]*entity.APIKey, error)
+	APIKeysAuthenticate(string) (*entity.User, *entity.APIKey, error)
 
 	ListsCreate(*entity.List) error
 	ListsFindByID(int, int) (*entity.List, error)
 	ListsEdit(*entity.List) (*entity.List, error)
 	ListsDelete(*entity.List) error
-	ListsFindByUser(int) ([]*entity.List, error)
+	ListsFindByUser(int, bool) ([]*entity.List, error)
+	ListsMove(*entity.List, int, int) (*entity.List, error)
+	ListsArchive(*entity.List) (*entity.List, error)
+	ListsUnarchive(*entity.List) (*entity.List, error)
+	ListsCreateFromTemplate(*entity.List, *entity.Template, *entity.Date, *time.Location) error
+
+	TemplatesCreate(*entity.Template, *entity.List, *time.Location) error
+	TemplatesFindByID(int, int) (*entity.Template, error)
+	TemplatesDelete(*entity.Template) error
+	TemplatesFindByUser(int) ([]*entity.Template, error)
+
+	TasksCreate(*entity.Task) error
+	TasksFindByID(int, int) (*entity.Task, error)
+	TasksEdit(*entity.Task) (*entity.Task, error)
+	TasksDelete(*entity.Task) error
+	TasksFindByList(int) ([]*entity.Task, error)
+	TasksFindByFilter(*entity.TaskFilter) ([]*entity.Task, error)
+	TasksMove(*entity.Task, int, int, int) (*entity.Task, error)
+
+	LabelsCreate(*entity.Label) error
+	LabelsFindByID(int, int) (*entity.Label, error)
+	LabelsEdit(*entity.Label) (*entity.Label, error)
+	LabelsDelete(*entity.Label) error
+	LabelsFindByUser(int) ([]*entity.Label, error)
+	LabelsAttach(int, int) error
+	LabelsDetach(int, int) error
+
+	ViewsCreate(*entity.View) error
+	ViewsFindByID(int, int) (*entity.View, error)
+	ViewsFindByKey(string, int) (*entity.View, error)
+	ViewsEdit(*entity.View) (*entity.View, error)
+	ViewsDelete(*entity.View) error
+	ViewsFindByUser(int) ([]*entity.View, error)
+	ViewsTasks(*entity.View, *entity.User) ([]*entity.Task, error)
+
+	ItemsCreate(*entity.Item) error
+	ItemsFindByID(int, int) (*entity.Item, error)
+	ItemsToggle(*entity.Item) (*entity.Item, error)
+	ItemsDelete(*entity.Item) error
+	ItemsFindByTask(int) ([]*entity.Item, error)
+	ItemsReorder(int, []int) ([]*entity.Item, error)
+
+	RemindersCreate(*entity.Reminder) error
+	RemindersFindByID(int, int) (*entity.Reminder, error)
+	RemindersDelete(*entity.Reminder) error
+	RemindersFindByTask(int) ([]*entity.Reminder, error)
+	RemindersSend(time.Time, int) (int, error)
+
+	DependenciesFindByTask(int) ([]*entity.Dependency, error)
+	DependenciesCreate(*entity.Dependency) error
+	DependenciesDelete(*entity.Dependency) error
 }
@@ -1,20 +1,63 @@
 package usecase
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
 	"github.com/AnatoliyBr/todo-app/internal/store"
+	"github.com/sirupsen/logrus"
 )
 
 type AppUseCase struct {
-	store store.Store
+	config   *Config
+	store    store.Store
+	notifier notify.Notifier
+	mailer   *email.Mailer
+	mfaKey   []byte
 }
 
-func NewAppUseCase(s store.Store) *AppUseCase {
+// NewAppUseCase logs notifications unless another notifier is set.
+func NewAppUseCase(config *Config, s store.Store) *AppUseCase {
 	return &AppUseCase{
-		store: s,
+		config:   config,
+		store:    s,
+		notifier: notify.NewLogNotifier(logrus.StandardLogger()),
 	}
 }
 
+// WithNotifier sets how reminders are delivered.
+func (uc *AppUseCase) WithNotifier(n notify.Notifier) *AppUseCase {
+	uc.notifier = n
+	return uc
+}
+
+// WithMailer sets how account emails are sent,
+// without it they fail with ErrNoMailer.
+func (uc *AppUseCase) WithMailer(m *email.Mailer) *AppUseCase {
+	uc.mailer = m
+	return uc
+}
+
+// WithMFAKey sets the key the TOTP secrets are encrypted with,
+// without it two-factor authentication fails with ErrNoMFAKey.
+func (uc *AppUseCase) WithMFAKey(key []byte) *AppUseCase {
+	uc.mfaKey = key
+	return uc
+}
+
+// withStore returns a copy of the use case working with the store,
+// it runs the use cases inside a transaction.
+func (uc *AppUseCase) withStore(s store.Store) *AppUseCase {
+	tx := *uc
+	tx.store = s
+	return &tx
+}
+
 func (uc *AppUseCase) UsersCreate(u *entity.User) error {
 	return uc.store.User().Create(u)
 }
@@ -27,12 +70,597 @@ func (uc *AppUseCase) UsersFindByEmail(email string) (*entity.User, error) {
 	return uc.store.User().FindByEmail(email)
 }
 
+func (uc *AppUseCase) UsersEditTimezone(u *entity.User) (*entity.User, error) {
+	return uc.store.User().EditTimezone(u)
+}
+
+// UsersFindOrCreateVerified returns the user with the email an identity
+// provider has verified, signing up one with a random password if there is
+// none. The user can set a password later with a password reset.
+func (uc *AppUseCase) UsersFindOrCreateVerified(emailAddr string) (*entity.User, error) {
+	u, err := uc.store.User().FindByEmail(emailAddr)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if u == nil {
+		password, err := entity.NewToken()
+		if err != nil {
+			return nil, err
+		}
+
+		u = &entity.User{Email: emailAddr, Password: password}
+		if err := uc.store.User().Create(u); err != nil {
+			return nil, err
+		}
+		u.Sanitize()
+	}
+
+	if !u.Verified() {
+		if err := uc.store.User().Verify(u); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// UsersEditPassword sets the new password of the user if the current one
+// matches. The tokens issued before are revoked.
+func (uc *AppUseCase) UsersEditPassword(u *entity.User, current, password string) error {
+	if !u.ComparePassword(current) {
+		return ErrIncorrectPassword
+	}
+
+	u.Password = password
+	err := uc.store.User().EditPassword(u)
+	u.Sanitize()
+	return err
+}
+
+// UsersEditEmail sets the new email of the user if the password matches.
+// The new email has to be verified again, the verifications sent to
+// the old one stop working.
+func (uc *AppUseCase) UsersEditEmail(u *entity.User, password, emailAddr string) error {
+	if !u.ComparePassword(password) {
+		return ErrIncorrectPassword
+	}
+
+	other, err := uc.store.User().FindByEmail(emailAddr)
+	if err != nil && err != store.ErrRecordNotFound {
+		return err
+	}
+	if other != nil && other.UserID != u.UserID {
+		return ErrEmailTaken
+	}
+
+	changed := *u
+	changed.Email = emailAddr
+	if err := uc.store.Transaction(func(s store.Store) error {
+		if err := s.User().EditEmail(&changed); err != nil {
+			return err
+		}
+		return s.EmailVerification().DeleteByUser(u.UserID)
+	}); err != nil {
+		return err
+	}
+
+	*u = changed
+	return nil
+}
+
+// UsersDelete deletes the user with all the data of the user
+// if the password matches.
+func (uc *AppUseCase) UsersDelete(u *entity.User, password string) error {
+	if !u.ComparePassword(password) {
+		return ErrIncorrectPassword
+	}
+	return uc.store.User().Delete(u)
+}
+
+func (uc *AppUseCase) UsersFindAll() ([]*entity.User, error) {
+	return uc.store.User().FindAll()
+}
+
+// UsersDisable disables the account of the user and ends
+// the sessions of the user, API keys stop working too.
+func (uc *AppUseCase) UsersDisable(u *entity.User) error {
+	return uc.store.User().Disable(u)
+}
+
+func (uc *AppUseCase) UsersEnable(u *entity.User) error {
+	return uc.store.User().Enable(u)
+}
+
+// UsersRevokeTokens ends the sessions of the user.
+func (uc *AppUseCase) UsersRevokeTokens(u *entity.User) error {
+	return uc.store.User().RevokeTokens(u)
+}
+
+// UsersUsage counts what the user has created, archived lists included.
+// The repositories tell there are none with ErrRecordNotFound.
+func (uc *AppUseCase) UsersUsage(userID int) (*entity.Usage, error) {
+	usage := &entity.Usage{}
+
+	for _, archived := range []bool{false, true} {
+		lists, err := uc.store.List().FindByUser(userID, archived)
+		if err != nil {
+			return nil, err
+		}
+		usage.Lists += len(lists)
+
+		for _, l := range lists {
+			tasks, err := uc.store.Task().FindByList(l.ListID)
+			if err != nil && err != store.ErrRecordNotFound {
+				return nil, err
+			}
+			usage.Tasks += len(tasks)
+		}
+	}
+
+	labels, err := uc.store.Label().FindByUser(userID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+	usage.Labels = len(labels)
+
+	views, err := uc.store.View().FindByUser(userID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+	usage.Views = len(views)
+
+	templates, err := uc.store.Template().FindByUser(userID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+	usage.Templates = len(templates)
+
+	keys, err := uc.store.APIKey().FindByUser(userID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+	usage.APIKeys = len(keys)
+
+	return usage, nil
+}
+
+// Login returns the user with the email if the password matches,
+// recording the attempt made from the IP address. A password hashed
+// with outdated parameters is hashed again. After repeated failures
+// for the email or the IP address further attempts fail with
+// LoginLockedError until the lockout, doubled on every failure, ends.
+func (uc *AppUseCase) Login(emailAddr, password, ip string) (*entity.User, error) {
+	// the case of the email doesn't give more attempts
+	key := strings.ToLower(emailAddr)
+	now := time.Now()
+
+	retryAfter, err := uc.loginLockout(key, ip, now)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		return nil, &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	a := &entity.LoginAttempt{Email: key, IP: ip, CreatedAt: now}
+
+	u, err := uc.store.User().FindByEmail(emailAddr)
+	if err != nil && err != store.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if u == nil {
+		entity.CompareDummyPassword(password)
+	} else {
+		a.UserID = &u.UserID
+		a.Succeeded = u.ComparePassword(password)
+	}
+
+	if err := uc.store.LoginAttempt().Create(a); err != nil {
+		return nil, err
+	}
+
+	if !a.Succeeded {
+		return nil, ErrIncorrectEmailOrPassword
+	}
+
+	if u.NeedsRehash() {
+		if err := u.Rehash(password); err != nil {
+			return nil, err
+		}
+
+		if err := uc.store.User().EditPasswordHash(u); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// LoginMFA finishes the login of the user with the second factor.
+// The checks are recorded like the password logins. After mfa_free_attempts
+// failures in a row the step is locked out like the password one and the
+// tokens of the user are revoked, so the MFA pending token can't be used
+// to guess more codes.
+func (uc *AppUseCase) LoginMFA(u *entity.User, code, ip string) error {
+	now := time.Now()
+
+	failures, err := uc.store.LoginAttempt().MFAFailuresByUser(u.UserID, now.Add(-uc.config.LoginAttemptsWindow))
+	if err != nil {
+		return err
+	}
+
+	if until := failures.LockedUntil(uc.config.MFAFreeAttempts, uc.config.LoginLockout, uc.config.LoginMaxLockout); until.After(now) {
+		return &LoginLockedError{RetryAfter: until.Sub(now)}
+	}
+
+	err = uc.MFAVerify(u.UserID, code)
+	if err != nil && err != ErrInvalidMFACode {
+		return err
+	}
+
+	a := &entity.LoginAttempt{
+		Email:     strings.ToLower(u.Email),
+		IP:        ip,
+		UserID:    &u.UserID,
+		Succeeded: err == nil,
+		MFA:       true,
+		CreatedAt: now,
+	}
+	if err := uc.store.LoginAttempt().Create(a); err != nil {
+		return err
+	}
+
+	if a.Succeeded {
+		return nil
+	}
+
+	if failures.Count+1 >= uc.config.MFAFreeAttempts {
+		if err := uc.store.User().RevokeTokens(u); err != nil {
+			return err
+		}
+	}
+	return ErrInvalidMFACode
+}
+
+// loginLockout returns how long the email or the IP address is locked out.
+func (uc *AppUseCase) loginLockout(emailAddr, ip string, now time.Time) (time.Duration, error) {
+	since := now.Add(-uc.config.LoginAttemptsWindow)
+
+	byEmail, err := uc.store.LoginAttempt().FailuresByEmail(emailAddr, since)
+	if err != nil {
+		return 0, err
+	}
+
+	byIP, err := uc.store.LoginAttempt().FailuresByIP(ip, since)
+	if err != nil {
+		return 0, err
+	}
+
+	until := byEmail.LockedUntil(uc.config.LoginFreeAttempts, uc.config.LoginLockout, uc.config.LoginMaxLockout)
+	if ipUntil := byIP.LockedUntil(uc.config.LoginIPFreeAttempts, uc.config.LoginLockout, uc.config.LoginMaxLockout); ipUntil.After(until) {
+		until = ipUntil
+	}
+
+	return until.Sub(now), nil
+}
+
+// PasswordResetsCreate mails a password reset link to the user with the email.
+// Unknown emails are ignored, so the caller can't tell which accounts exist.
+func (uc *AppUseCase) PasswordResetsCreate(emailAddr string) error {
+	u, err := uc.store.User().FindByEmail(emailAddr)
+	if err == store.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p, token, err := entity.NewPasswordReset(u.UserID, uc.config.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.store.PasswordReset().Create(p); err != nil {
+		return err
+	}
+
+	return uc.mail("password_reset", u.Email, &tokenMail{
+		URL:       uc.config.PublicURL + "/password-resets/" + token,
+		ExpiresAt: p.ExpiresAt.In(u.Location()),
+	})
+}
+
+// PasswordResetsConfirm sets the new password of the user the token was
+// issued to. The token works once, and the tokens issued before are revoked.
+func (uc *AppUseCase) PasswordResetsConfirm(token, password string) error {
+	p, err := uc.store.PasswordReset().FindByToken(entity.HashToken(token))
+	if err == store.ErrRecordNotFound {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if !p.Usable(time.Now()) {
+		return ErrInvalidResetToken
+	}
+
+	return uc.store.Transaction(func(s store.Store) error {
+		u, err := s.User().FindByID(p.UserID)
+		if err != nil {
+			return err
+		}
+
+		u.Password = password
+		if err := s.User().EditPassword(u); err != nil {
+			return err
+		}
+
+		if err := s.PasswordReset().MarkUsed(p); err != nil {
+			if err == store.ErrRecordNotFound {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// EmailVerificationsSend mails the user a token confirming the email.
+// It fails with ErrVerificationTooSoon if the last one was sent
+// less than the resend interval ago.
+func (uc *AppUseCase) EmailVerificationsSend(u *entity.User) error {
+	if u.Verified() {
+		return ErrEmailVerified
+	}
+
+	last, err := uc.store.EmailVerification().FindLastByUser(u.UserID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return err
+	}
+
+	if last != nil && time.Since(last.CreatedAt) < uc.config.VerificationResendInterval {
+		return ErrVerificationTooSoon
+	}
+
+	v, token, err := entity.NewEmailVerification(u.UserID, uc.config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.store.EmailVerification().Create(v); err != nil {
+		return err
+	}
+
+	return uc.mail("email_verification", u.Email, &tokenMail{
+		URL:       uc.config.PublicURL + "/users/verify",
+		Token:     token,
+		ExpiresAt: v.ExpiresAt.In(u.Location()),
+	})
+}
+
+// EmailVerificationsConfirm marks the email of the user the token was sent to
+// as verified, confirming an already verified email again is a no-op.
+func (uc *AppUseCase) EmailVerificationsConfirm(token string) error {
+	v, err := uc.store.EmailVerification().FindByToken(entity.HashToken(token))
+	if err == store.ErrRecordNotFound {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if !v.Usable(time.Now()) {
+		return ErrInvalidVerificationToken
+	}
+
+	return uc.store.User().Verify(&entity.User{UserID: v.UserID})
+}
+
+// MFAEnroll sets up a new TOTP secret for the user and returns it with
+// the otpauth URI for an authenticator app. The login keeps working with
+// the password only until the user confirms the secret with MFAConfirm.
+func (uc *AppUseCase) MFAEnroll(u *entity.User) (string, string, error) {
+	m, err := uc.store.MFA().FindByUser(u.UserID)
+	if err != nil && err != store.ErrRecordNotFound {
+		return "", "", err
+	}
+
+	if m != nil && m.Enabled() {
+		return "", "", ErrMFAEnabled
+	}
+
+	secret, err := entity.NewTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	key, err := uc.secretKey()
+	if err != nil {
+		return "", "", err
+	}
+
+	sealed, err := entity.SealSecret(key, secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := uc.store.MFA().Save(&entity.MFA{UserID: u.UserID, EncryptedSecret: sealed}); err != nil {
+		return "", "", err
+	}
+	return secret, entity.TOTPURI(secret, uc.config.MFAIssuer, u.Email), nil
+}
+
+// MFAConfirm enables the enrolled secret once the user proves the app
+// generates its codes, and returns the recovery codes of the user.
+func (uc *AppUseCase) MFAConfirm(u *entity.User, code string) ([]string, error) {
+	m, err := uc.store.MFA().FindByUser(u.UserID)
+	if err == store.ErrRecordNotFound {
+		return nil, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Enabled() {
+		return nil, ErrMFAEnabled
+	}
+
+	if err := uc.checkTOTP(m, code); err != nil {
+		return nil, err
+	}
+
+	codes, plain, err := entity.NewRecoveryCodes(u.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.store.Transaction(func(s store.Store) error {
+		if err := s.MFA().Enable(m); err != nil {
+			return err
+		}
+		return s.RecoveryCode().Replace(u.UserID, codes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+func (uc *AppUseCase) MFAEnabled(userID int) (bool, error) {
+	m, err := uc.store.MFA().FindByUser(userID)
+	if err == store.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return m.Enabled(), nil
+}
+
+// MFAVerify accepts either a TOTP code or an unused recovery code of the user.
+func (uc *AppUseCase) MFAVerify(userID int, code string) error {
+	m, err := uc.store.MFA().FindByUser(userID)
+	if err == store.ErrRecordNotFound {
+		return ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	if !m.Enabled() {
+		return ErrMFANotEnrolled
+	}
+
+	err = uc.checkTOTP(m, code)
+	if err != ErrInvalidMFACode {
+		return err
+	}
+
+	err = uc.store.RecoveryCode().Use(userID, entity.HashRecoveryCode(code))
+	if err == store.ErrRecordNotFound {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// MFADisable turns off the second factor after checking a code,
+// so a stolen session alone can't turn it off.
+func (uc *AppUseCase) MFADisable(u *entity.User, code string) error {
+	if err := uc.MFAVerify(u.UserID, code); err != nil {
+		return err
+	}
+
+	return uc.store.Transaction(func(s store.Store) error {
+		if err := s.MFA().Delete(&entity.MFA{UserID: u.UserID}); err != nil {
+			return err
+		}
+		return s.RecoveryCode().Replace(u.UserID, nil)
+	})
+}
+
+// APIKeysCreate stores a new key and returns it,
+// only its hash and prefix are kept.
+func (uc *AppUseCase) APIKeysCreate(k *entity.APIKey) (string, error) {
+	key, err := k.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.store.APIKey().Create(k); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (uc *AppUseCase) APIKeysFindByID(apiKeyID, userID int) (*entity.APIKey, error) {
+	return uc.store.APIKey().FindByID(apiKeyID, userID)
+}
+
+func (uc *AppUseCase) APIKeysDelete(k *entity.APIKey) error {
+	return uc.store.APIKey().Delete(k)
+}
+
+func (uc *AppUseCase) APIKeysFindByUser(userID int) ([]*entity.APIKey, error) {
+	return uc.store.APIKey().FindByUser(userID)
+}
+
+// APIKeysAuthenticate returns the user of an unexpired key with the key
+// itself and records the use of the key.
+func (uc *AppUseCase) APIKeysAuthenticate(key string) (*entity.User, *entity.APIKey, error) {
+	k, err := uc.store.APIKey().FindByHash(entity.HashToken(key))
+	if err == store.ErrRecordNotFound {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if k.Expired(time.Now()) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	u, err := uc.store.User().FindByID(k.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.store.APIKey().Touch(k); err != nil {
+		return nil, nil, err
+	}
+	return u, k, nil
+}
+
+// ListsCreate puts the new list after all other lists of the user.
 func (uc *AppUseCase) ListsCreate(l *entity.List) error {
+	if err := uc.checkVerified(l.UserID); err != nil {
+		return err
+	}
+
+	lists, err := uc.store.List().FindByUser(l.UserID, false)
+	if err != nil {
+		return err
+	}
+
+	last := ""
+	if len(lists) > 0 {
+		last = lists[len(lists)-1].Position
+	}
+
+	l.Position = entity.PositionBetween(last, "")
 	return uc.store.List().Create(l)
 }
 
 func (uc *AppUseCase) ListsFindByID(listID, userID int) (*entity.List, error) {
-	return uc.store.List().FindByID(listID, userID)
+	l, err := uc.store.List().FindByID(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.setListComputed(l); err != nil {
+		return nil, err
+	}
+	return l, nil
 }
 
 func (uc *AppUseCase) ListsEdit(l *entity.List) (*entity.List, error) {
@@ -43,6 +671,777 @@ func (uc *AppUseCase) ListsDelete(l *entity.List) error {
 	return uc.store.List().Delete(l)
 }
 
-func (uc *AppUseCase) ListsFindByUser(userID int) ([]*entity.List, error) {
-	return uc.store.List().FindByUser(userID)
+// ListsFindByUser returns either the archived lists of the user or the other ones.
+func (uc *AppUseCase) ListsFindByUser(userID int, archived bool) ([]*entity.List, error) {
+	lists, err := uc.store.List().FindByUser(userID, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.setListComputed(lists...); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// ListsMove places the list right after or before another list of the user,
+// or at the end if no anchor is given.
+func (uc *AppUseCase) ListsMove(l *entity.List, afterID, beforeID int) (*entity.List, error) {
+	lists, err := uc.store.List().FindByUser(l.UserID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(lists))
+	positions := make([]string, 0, len(lists))
+	for _, list := range lists {
+		if list.ListID != l.ListID {
+			ids = append(ids, list.ListID)
+			positions = append(positions, list.Position)
+		}
+	}
+
+	l.Position, err = place(ids, positions, afterID, beforeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.store.List().Move(l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (uc *AppUseCase) ListsArchive(l *entity.List) (*entity.List, error) {
+	if l.Archived() {
+		return l, nil
+	}
+
+	l.ArchivedAt = &entity.TimeISO{Time: time.Now().UTC().Truncate(time.Second)}
+	if err := uc.store.List().Archive(l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListsUnarchive returns the list after all other lists of the user.
+func (uc *AppUseCase) ListsUnarchive(l *entity.List) (*entity.List, error) {
+	if !l.Archived() {
+		return l, nil
+	}
+
+	err := uc.store.Transaction(func(s store.Store) error {
+		tx := uc.withStore(s)
+
+		l.ArchivedAt = nil
+		if err := s.List().Archive(l); err != nil {
+			return err
+		}
+
+		_, err := tx.ListsMove(l, 0, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListsCreateFromTemplate creates the list with the tasks and checklist
+// items of the template in one transaction, the first tasks are due
+// on the start day in the given location.
+func (uc *AppUseCase) ListsCreateFromTemplate(l *entity.List, t *entity.Template, start *entity.Date, loc *time.Location) error {
+	return uc.store.Transaction(func(s store.Store) error {
+		tx := uc.withStore(s)
+
+		if err := tx.ListsCreate(l); err != nil {
+			return err
+		}
+
+		for _, tt := range t.Tasks {
+			task := tt.Task(start, loc)
+			task.ListID = l.ListID
+			if err := tx.TasksCreate(task); err != nil {
+				return err
+			}
+
+			for _, title := range tt.Items {
+				if err := tx.ItemsCreate(&entity.Item{ItemTitle: title, TaskID: task.TaskID}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// TemplatesCreate saves the tasks of the list with their checklist items
+// into the template, deadlines are made relative in the given location.
+func (uc *AppUseCase) TemplatesCreate(t *entity.Template, l *entity.List, loc *time.Location) error {
+	tasks, err := uc.store.Task().FindByList(l.ListID)
+	if err != nil {
+		return err
+	}
+
+	items := make(map[int][]*entity.Item, len(tasks))
+	for _, task := range tasks {
+		items[task.TaskID], err = uc.store.Item().FindByTask(task.TaskID)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.Tasks = entity.NewTemplateTasks(tasks, items, loc)
+	return uc.store.Template().Create(t)
+}
+
+func (uc *AppUseCase) TemplatesFindByID(templateID, userID int) (*entity.Template, error) {
+	return uc.store.Template().FindByID(templateID, userID)
+}
+
+func (uc *AppUseCase) TemplatesDelete(t *entity.Template) error {
+	return uc.store.Template().Delete(t)
+}
+
+func (uc *AppUseCase) TemplatesFindByUser(userID int) ([]*entity.Template, error) {
+	return uc.store.Template().FindByUser(userID)
+}
+
+// TasksCreate puts the new task at the end of its list.
+func (uc *AppUseCase) TasksCreate(t *entity.Task) error {
+	if err := uc.checkWritable(t.ListID); err != nil {
+		return err
+	}
+
+	tasks, err := uc.store.Task().FindByList(t.ListID)
+	if err != nil {
+		return err
+	}
+
+	last := ""
+	if len(tasks) > 0 {
+		last = tasks[len(tasks)-1].Position
+	}
+
+	t.Position = entity.PositionBetween(last, "")
+	return uc.store.Task().Create(t)
+}
+
+// TasksFindByID returns the task only if it belongs to one of the user's lists.
+func (uc *AppUseCase) TasksFindByID(taskID, userID int) (*entity.Task, error) {
+	t, err := uc.store.Task().FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.store.List().FindByID(t.ListID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.setComputed(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// TasksEdit refuses to mark a task done while its blockers are open
+// if strict dependencies are configured.
+func (uc *AppUseCase) TasksEdit(t *entity.Task) (*entity.Task, error) {
+	if err := uc.checkWritable(t.ListID); err != nil {
+		return nil, err
+	}
+
+	if uc.config.StrictDependencies && t.Done {
+		prev, err := uc.store.Task().FindByID(t.TaskID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !prev.Done {
+			open, err := uc.store.Dependency().OpenBlockers(t.TaskID)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(open[t.TaskID]) > 0 {
+				return nil, ErrTaskBlocked
+			}
+		}
+	}
+
+	t, err := uc.store.Task().Edit(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.setComputed(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (uc *AppUseCase) TasksDelete(t *entity.Task) error {
+	if err := uc.checkWritable(t.ListID); err != nil {
+		return err
+	}
+	return uc.store.Task().Delete(t)
+}
+
+func (uc *AppUseCase) TasksFindByList(listID int) ([]*entity.Task, error) {
+	tasks, err := uc.store.Task().FindByList(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.setComputed(tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (uc *AppUseCase) TasksFindByFilter(f *entity.TaskFilter) ([]*entity.Task, error) {
+	tasks, err := uc.store.Task().FindByFilter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.setComputed(tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// TasksMove places the task right after or before another task of the
+// target list, or at its end if no anchor is given. Only the moved task
+// gets a new position, so no other rows are rewritten.
+func (uc *AppUseCase) TasksMove(t *entity.Task, listID, afterID, beforeID int) (*entity.Task, error) {
+	if listID == 0 {
+		listID = t.ListID
+	}
+
+	if err := uc.checkWritable(t.ListID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkWritable(listID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.store.Task().FindByList(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(tasks))
+	positions := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.TaskID != t.TaskID {
+			ids = append(ids, task.TaskID)
+			positions = append(positions, task.Position)
+		}
+	}
+
+	position, err := place(ids, positions, afterID, beforeID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.ListID = listID
+	t.Position = position
+	if err := uc.store.Task().Move(t); err != nil {
+		return nil, err
+	}
+
+	if err := uc.setComputed(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (uc *AppUseCase) LabelsCreate(l *entity.Label) error {
+	return uc.store.Label().Create(l)
+}
+
+func (uc *AppUseCase) LabelsFindByID(labelID, userID int) (*entity.Label, error) {
+	return uc.store.Label().FindByID(labelID, userID)
+}
+
+func (uc *AppUseCase) LabelsEdit(l *entity.Label) (*entity.Label, error) {
+	return uc.store.Label().Edit(l)
+}
+
+func (uc *AppUseCase) LabelsDelete(l *entity.Label) error {
+	return uc.store.Label().Delete(l)
+}
+
+func (uc *AppUseCase) LabelsFindByUser(userID int) ([]*entity.Label, error) {
+	return uc.store.Label().FindByUser(userID)
+}
+
+func (uc *AppUseCase) LabelsAttach(taskID, labelID int) error {
+	if err := uc.checkTaskWritable(taskID); err != nil {
+		return err
+	}
+	return uc.store.Label().Attach(taskID, labelID)
+}
+
+func (uc *AppUseCase) LabelsDetach(taskID, labelID int) error {
+	if err := uc.checkTaskWritable(taskID); err != nil {
+		return err
+	}
+	return uc.store.Label().Detach(taskID, labelID)
+}
+
+func (uc *AppUseCase) ViewsCreate(v *entity.View) error {
+	return uc.store.View().Create(v)
+}
+
+func (uc *AppUseCase) ViewsFindByID(viewID, userID int) (*entity.View, error) {
+	return uc.store.View().FindByID(viewID, userID)
+}
+
+// ViewsFindByKey finds a saved view by its id or a built-in view by its key.
+func (uc *AppUseCase) ViewsFindByKey(key string, userID int) (*entity.View, error) {
+	if viewID, err := strconv.Atoi(key); err == nil {
+		return uc.store.View().FindByID(viewID, userID)
+	}
+
+	if v, ok := entity.BuiltinView(key); ok {
+		return v, nil
+	}
+	return nil, store.ErrRecordNotFound
+}
+
+func (uc *AppUseCase) ViewsEdit(v *entity.View) (*entity.View, error) {
+	return uc.store.View().Edit(v)
+}
+
+func (uc *AppUseCase) ViewsDelete(v *entity.View) error {
+	return uc.store.View().Delete(v)
+}
+
+// ViewsFindByUser returns the built-in views followed by the saved ones.
+func (uc *AppUseCase) ViewsFindByUser(userID int) ([]*entity.View, error) {
+	views, err := uc.store.View().FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return append(entity.BuiltinViews(), views...), nil
+}
+
+// ViewsTasks finds the tasks of the user matching the view,
+// relative due ranges are resolved against the current day of the user.
+func (uc *AppUseCase) ViewsTasks(v *entity.View, u *entity.User) ([]*entity.Task, error) {
+	f := *v.Filter
+	f.UserID = u.UserID
+	f.Resolve(time.Now(), u.Location())
+
+	return uc.TasksFindByFilter(&f)
+}
+
+func (uc *AppUseCase) ItemsCreate(i *entity.Item) error {
+	if err := uc.checkTaskWritable(i.TaskID); err != nil {
+		return err
+	}
+	return uc.store.Item().Create(i)
+}
+
+func (uc *AppUseCase) ItemsFindByID(itemID, taskID int) (*entity.Item, error) {
+	return uc.store.Item().FindByID(itemID, taskID)
+}
+
+func (uc *AppUseCase) ItemsToggle(i *entity.Item) (*entity.Item, error) {
+	if err := uc.checkTaskWritable(i.TaskID); err != nil {
+		return nil, err
+	}
+
+	i.Done = !i.Done
+	return uc.store.Item().Edit(i)
+}
+
+func (uc *AppUseCase) ItemsDelete(i *entity.Item) error {
+	if err := uc.checkTaskWritable(i.TaskID); err != nil {
+		return err
+	}
+	return uc.store.Item().Delete(i)
+}
+
+func (uc *AppUseCase) ItemsFindByTask(taskID int) ([]*entity.Item, error) {
+	return uc.store.Item().FindByTask(taskID)
+}
+
+// ItemsReorder moves the items of the task into the order of itemIDs,
+// which must mention every item of the task exactly once.
+func (uc *AppUseCase) ItemsReorder(taskID int, itemIDs []int) ([]*entity.Item, error) {
+	if err := uc.checkTaskWritable(taskID); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.store.Item().FindByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) != len(itemIDs) {
+		return nil, ErrInvalidItemsOrder
+	}
+
+	known := make(map[int]bool, len(items))
+	for _, i := range items {
+		known[i.ItemID] = true
+	}
+
+	for _, id := range itemIDs {
+		if !known[id] {
+			return nil, ErrInvalidItemsOrder
+		}
+		delete(known, id)
+	}
+
+	if err := uc.store.Item().Reorder(taskID, itemIDs); err != nil {
+		return nil, err
+	}
+
+	return uc.store.Item().FindByTask(taskID)
+}
+
+func (uc *AppUseCase) RemindersCreate(r *entity.Reminder) error {
+	if err := uc.checkTaskWritable(r.TaskID); err != nil {
+		return err
+	}
+	return uc.store.Reminder().Create(r)
+}
+
+func (uc *AppUseCase) RemindersFindByID(reminderID, taskID int) (*entity.Reminder, error) {
+	return uc.store.Reminder().FindByID(reminderID, taskID)
+}
+
+func (uc *AppUseCase) RemindersDelete(r *entity.Reminder) error {
+	if err := uc.checkTaskWritable(r.TaskID); err != nil {
+		return err
+	}
+	return uc.store.Reminder().Delete(r)
+}
+
+func (uc *AppUseCase) RemindersFindByTask(taskID int) ([]*entity.Reminder, error) {
+	return uc.store.Reminder().FindByTask(taskID)
+}
+
+// RemindersSend delivers up to limit reminders due at now and returns how many
+// were sent. The reminders are claimed in a short transaction and delivered
+// after it commits, so a slow notifier doesn't hold row locks. Claiming puts off
+// the next attempt with backoff, so a failed reminder is retried later without
+// blocking the rest, and several instances may run it at once.
+func (uc *AppUseCase) RemindersSend(now time.Time, limit int) (int, error) {
+	var reminders []*entity.Reminder
+	err := uc.store.Transaction(func(s store.Store) error {
+		var err error
+		reminders, err = s.Reminder().FindDue(now, limit)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reminders {
+			r.Attempt(now)
+			if err := s.Reminder().MarkAttempted(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, r := range reminders {
+		if err := uc.reminderSend(r, now); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (uc *AppUseCase) reminderSend(r *entity.Reminder, now time.Time) error {
+	t, err := uc.store.Task().FindByID(r.TaskID)
+	if err != nil {
+		return err
+	}
+
+	u, err := uc.store.User().FindByID(r.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.notifier.Notify(&entity.Notification{User: u, Task: t, Reminder: r}); err != nil {
+		return err
+	}
+
+	r.SentAt = &entity.TimeISO{Time: now}
+	return uc.store.Reminder().MarkSent(r)
+}
+
+func (uc *AppUseCase) DependenciesFindByTask(taskID int) ([]*entity.Dependency, error) {
+	blockers, err := uc.store.Dependency().FindBlockers(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]*entity.Dependency, len(blockers))
+	for n, id := range blockers {
+		dependencies[n] = &entity.Dependency{TaskID: taskID, BlockerID: id}
+	}
+	return dependencies, nil
+}
+
+// DependenciesCreate rejects dependencies that would close a cycle,
+// including a task blocking itself.
+func (uc *AppUseCase) DependenciesCreate(d *entity.Dependency) error {
+	if err := uc.checkTaskWritable(d.TaskID); err != nil {
+		return err
+	}
+
+	blockers, err := uc.store.Dependency().FindBlockers(d.TaskID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range blockers {
+		if id == d.BlockerID {
+			return ErrDependencyExists
+		}
+	}
+
+	cycle, err := uc.reaches(d.BlockerID, d.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	return uc.store.Dependency().Create(d)
+}
+
+func (uc *AppUseCase) DependenciesDelete(d *entity.Dependency) error {
+	if err := uc.checkTaskWritable(d.TaskID); err != nil {
+		return err
+	}
+	return uc.store.Dependency().Delete(d)
+}
+
+// checkTOTP checks the TOTP code against the secret of the user,
+// a code is accepted only once.
+func (uc *AppUseCase) checkTOTP(m *entity.MFA, code string) error {
+	key, err := uc.secretKey()
+	if err != nil {
+		return err
+	}
+
+	secret, err := entity.OpenSecret(key, m.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := entity.MatchTOTP(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	if err := uc.store.MFA().UseStep(m, step); err != nil {
+		if err == store.ErrRecordNotFound {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return nil
+}
+
+// secretKey returns the key the TOTP secrets are encrypted with.
+func (uc *AppUseCase) secretKey() ([]byte, error) {
+	if uc.mfaKey == nil {
+		return nil, ErrNoMFAKey
+	}
+	return uc.mfaKey, nil
+}
+
+// checkVerified refuses the user with an unverified email
+// when the config requires verification.
+func (uc *AppUseCase) checkVerified(userID int) error {
+	if !uc.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	u, err := uc.store.User().FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.Verified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// checkWritable refuses changes to the tasks of an archived list.
+func (uc *AppUseCase) checkWritable(listID int) error {
+	archived, err := uc.store.List().IsArchived(listID)
+	if err != nil {
+		return err
+	}
+
+	if archived {
+		return ErrListArchived
+	}
+	return nil
+}
+
+// checkTaskWritable refuses changes to a task of an archived list
+// and to its checklist, dependencies and labels.
+func (uc *AppUseCase) checkTaskWritable(taskID int) error {
+	t, err := uc.store.Task().FindByID(taskID)
+	if err != nil {
+		return err
+	}
+	return uc.checkWritable(t.ListID)
+}
+
+// reaches walks the blockers starting from the given task
+// and reports whether the target task is among them.
+func (uc *AppUseCase) reaches(from, target int) (bool, error) {
+	visited := make(map[int]bool)
+	stack := []int{from}
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if id == target {
+			return true, nil
+		}
+
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		blockers, err := uc.store.Dependency().FindBlockers(id)
+		if err != nil {
+			return false, err
+		}
+		stack = append(stack, blockers...)
+	}
+
+	return false, nil
+}
+
+// tokenMail is the data of the emails with a token,
+// either in the link or next to it.
+type tokenMail struct {
+	URL       string
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (uc *AppUseCase) mail(name, to string, data interface{}) error {
+	if uc.mailer == nil {
+		return ErrNoMailer
+	}
+	return uc.mailer.Send(name, to, data)
+}
+
+// setComputed fills the fields that are not stored with the task.
+func (uc *AppUseCase) setComputed(tasks ...*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(tasks))
+	for n, t := range tasks {
+		ids[n] = t.TaskID
+	}
+
+	progress, err := uc.store.Item().Progress(ids...)
+	if err != nil {
+		return err
+	}
+
+	open, err := uc.store.Dependency().OpenBlockers(ids...)
+	if err != nil {
+		return err
+	}
+
+	labels, err := uc.store.Label().FindByTasks(ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		t.Progress = progress[t.TaskID]
+		t.Blocked = len(open[t.TaskID]) > 0
+		t.Labels = labels[t.TaskID]
+		if t.Labels == nil {
+			t.Labels = make([]*entity.Label, 0)
+		}
+	}
+	return nil
+}
+
+// setListComputed fills the fields that are not stored with the list.
+func (uc *AppUseCase) setListComputed(lists ...*entity.List) error {
+	if len(lists) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(lists))
+	for n, l := range lists {
+		ids[n] = l.ListID
+	}
+
+	estimates, err := uc.store.Task().RemainingEstimates(ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range lists {
+		l.RemainingEstimate = estimates[l.ListID]
+	}
+	return nil
+}
+
+// place returns a position next to the anchor among ordered ids and positions.
+func place(ids []int, positions []string, afterID, beforeID int) (string, error) {
+	if afterID != 0 && beforeID != 0 {
+		return "", ErrInvalidMove
+	}
+
+	if afterID == 0 && beforeID == 0 {
+		if len(positions) == 0 {
+			return entity.PositionBetween("", ""), nil
+		}
+		return entity.PositionBetween(positions[len(positions)-1], ""), nil
+	}
+
+	for n, id := range ids {
+		switch id {
+		case afterID:
+			next := ""
+			if n+1 < len(positions) {
+				next = positions[n+1]
+			}
+			return entity.PositionBetween(positions[n], next), nil
+		case beforeID:
+			prev := ""
+			if n > 0 {
+				prev = positions[n-1]
+			}
+			return entity.PositionBetween(prev, positions[n]), nil
+		}
+	}
+
+	return "", ErrInvalidMove
 }
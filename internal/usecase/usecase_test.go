@@ -1,31 +1,34 @@
 package usecase_test
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/AnatoliyBr/todo-app/internal/entity"
+	"github.com/AnatoliyBr/todo-app/internal/notify/email"
 	"github.com/AnatoliyBr/todo-app/internal/store"
 	"github.com/AnatoliyBr/todo-app/internal/store/testrepository"
 	"github.com/AnatoliyBr/todo-app/internal/usecase"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAppUseCase_UsersCreate(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
+	s := testrepository.TestStore(t)
 	u := entity.TestUser(t)
-	uc := usecase.NewAppUseCase(s)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 
 	assert.NotNil(t, u)
 	assert.NoError(t, uc.UsersCreate(u))
 }
 
 func TestAppUseCase_UsersFindByID(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u1 := entity.TestUser(t)
 	_, err := uc.UsersFindByID(u1.UserID)
 	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
@@ -37,10 +40,8 @@ func TestAppUseCase_UsersFindByID(t *testing.T) {
 }
 
 func TestAppUseCase_UsersFindByEmail(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u1 := entity.TestUser(t)
 	_, err := uc.UsersFindByEmail(u1.Email)
 	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
@@ -51,11 +52,21 @@ func TestAppUseCase_UsersFindByEmail(t *testing.T) {
 	assert.NotNil(t, u2)
 }
 
+func TestAppUseCase_UsersEditTimezone(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u1 := entity.TestUser(t)
+	uc.UsersCreate(u1)
+
+	u1.Timezone = "Asia/Yekaterinburg"
+	u2, err := uc.UsersEditTimezone(u1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Yekaterinburg", u2.Timezone)
+}
+
 func TestAppUseCase_ListsCreate(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u := entity.TestUser(t)
 	l := entity.TestList(t)
 	uc.UsersCreate(u)
@@ -66,10 +77,8 @@ func TestAppUseCase_ListsCreate(t *testing.T) {
 }
 
 func TestAppUseCase_ListsFindByID(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	uc.UsersCreate(u)
@@ -82,13 +91,21 @@ func TestAppUseCase_ListsFindByID(t *testing.T) {
 	l2, err := uc.ListsFindByID(l1.ListID, u.UserID)
 	assert.NoError(t, err)
 	assert.NotNil(t, l2)
+	assert.Equal(t, 0, l2.RemainingEstimate)
+
+	task := entity.TestTask(t)
+	estimate := 25
+	task.Estimate = &estimate
+	task.ListID = l1.ListID
+	uc.TasksCreate(task)
+	l2, err = uc.ListsFindByID(l1.ListID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, l2.RemainingEstimate)
 }
 
 func TestAppUseCase_ListsEdit(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	l2 := entity.TestList(t)
@@ -104,10 +121,8 @@ func TestAppUseCase_ListsEdit(t *testing.T) {
 }
 
 func TestAppUseCase_ListsDelete(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u := entity.TestUser(t)
 	l := entity.TestList(t)
 	uc.UsersCreate(u)
@@ -122,10 +137,358 @@ func TestAppUseCase_ListsDelete(t *testing.T) {
 }
 
 func TestAppUseCase_ListsFindByUser(t *testing.T) {
-	ur := testrepository.NewUserRepository()
-	lr := testrepository.NewListRepository()
-	s := store.NewAppStore(ur, lr)
-	uc := usecase.NewAppUseCase(s)
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	uc.UsersCreate(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+
+	lists, err := uc.ListsFindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Empty(t, lists)
+
+	uc.ListsCreate(l1)
+	uc.ListsCreate(l2)
+	lists, err = uc.ListsFindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 2)
+}
+
+func TestAppUseCase_TasksCreate(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+
+	err := uc.TasksCreate(task)
+	assert.NoError(t, err)
+}
+
+func TestAppUseCase_TasksFindByID(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+
+	_, err := uc.TasksFindByID(t1.TaskID, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	uc.TasksCreate(t1)
+	i := entity.TestItem(t)
+	i.TaskID = t1.TaskID
+	uc.ItemsCreate(i)
+
+	t2, err := uc.TasksFindByID(t1.TaskID, u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Progress{Done: 0, Total: 1}, t2.Progress)
+
+	_, err = uc.TasksFindByID(t1.TaskID, u.UserID+1)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAppUseCase_TasksEdit(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	uc.TasksCreate(t1)
+
+	t1.Done = true
+	t2, err := uc.TasksEdit(t1)
+	assert.NoError(t, err)
+	assert.True(t, t2.Done)
+}
+
+func TestAppUseCase_TasksDelete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+	uc.TasksCreate(task)
+
+	err := uc.TasksDelete(task)
+	assert.NoError(t, err)
+
+	_, err = uc.TasksFindByID(task.TaskID, u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAppUseCase_TasksFindByList(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	uc.TasksCreate(t1)
+	uc.TasksCreate(t2)
+
+	i := entity.TestItem(t)
+	i.TaskID = t2.TaskID
+	i.Done = true
+	uc.ItemsCreate(i)
+
+	tasks, err := uc.TasksFindByList(l.ListID)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, entity.Progress{}, tasks[0].Progress)
+	assert.Equal(t, entity.Progress{Done: 1, Total: 1}, tasks[1].Progress)
+}
+
+func TestAppUseCase_ItemsToggle(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+	uc.TasksCreate(task)
+	i1 := entity.TestItem(t)
+	i1.TaskID = task.TaskID
+	uc.ItemsCreate(i1)
+
+	i2, err := uc.ItemsToggle(i1)
+	assert.NoError(t, err)
+	assert.True(t, i2.Done)
+
+	i2, err = uc.ItemsToggle(i2)
+	assert.NoError(t, err)
+	assert.False(t, i2.Done)
+}
+
+func TestAppUseCase_ItemsReorder(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+	uc.TasksCreate(task)
+	i1 := entity.TestItem(t)
+	i2 := entity.TestItem(t)
+	i1.TaskID = task.TaskID
+	i2.TaskID = task.TaskID
+	uc.ItemsCreate(i1)
+	uc.ItemsCreate(i2)
+
+	testCases := []struct {
+		name    string
+		itemIDs []int
+		isValid bool
+	}{
+		{
+			name:    "valid",
+			itemIDs: []int{i2.ItemID, i1.ItemID},
+			isValid: true,
+		},
+		{
+			name:    "missing item",
+			itemIDs: []int{i2.ItemID},
+			isValid: false,
+		},
+		{
+			name:    "duplicate item",
+			itemIDs: []int{i2.ItemID, i2.ItemID},
+			isValid: false,
+		},
+		{
+			name:    "unknown item",
+			itemIDs: []int{i2.ItemID, 42},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := uc.ItemsReorder(task.TaskID, tc.itemIDs)
+			if tc.isValid {
+				assert.NoError(t, err)
+				assert.Equal(t, i2.ItemID, items[0].ItemID)
+			} else {
+				assert.EqualError(t, err, usecase.ErrInvalidItemsOrder.Error())
+			}
+		})
+	}
+}
+
+func TestAppUseCase_ItemsDelete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+	uc.TasksCreate(task)
+	i := entity.TestItem(t)
+	i.TaskID = task.TaskID
+	uc.ItemsCreate(i)
+
+	err := uc.ItemsDelete(i)
+	assert.NoError(t, err)
+
+	_, err = uc.ItemsFindByID(i.ItemID, task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAppUseCase_DependenciesCreate(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+
+	tasks := make([]*entity.Task, 3)
+	for n := range tasks {
+		tasks[n] = entity.TestTask(t)
+		tasks[n].TaskTitle = fmt.Sprintf("test task %d", n+1)
+		tasks[n].ListID = l.ListID
+		uc.TasksCreate(tasks[n])
+	}
+
+	// 1 <- 2 <- 3
+	assert.NoError(t, uc.DependenciesCreate(&entity.Dependency{TaskID: 2, BlockerID: 1}))
+	assert.NoError(t, uc.DependenciesCreate(&entity.Dependency{TaskID: 3, BlockerID: 2}))
+
+	testCases := []struct {
+		name string
+		d    *entity.Dependency
+		err  error
+	}{
+		{
+			name: "exists",
+			d:    &entity.Dependency{TaskID: 2, BlockerID: 1},
+			err:  usecase.ErrDependencyExists,
+		},
+		{
+			name: "self",
+			d:    &entity.Dependency{TaskID: 1, BlockerID: 1},
+			err:  usecase.ErrDependencyCycle,
+		},
+		{
+			name: "direct cycle",
+			d:    &entity.Dependency{TaskID: 1, BlockerID: 2},
+			err:  usecase.ErrDependencyCycle,
+		},
+		{
+			name: "transitive cycle",
+			d:    &entity.Dependency{TaskID: 1, BlockerID: 3},
+			err:  usecase.ErrDependencyCycle,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, uc.DependenciesCreate(tc.d), tc.err.Error())
+		})
+	}
+
+	assert.NoError(t, uc.DependenciesCreate(&entity.Dependency{TaskID: 3, BlockerID: 1}))
+
+	task, err := uc.TasksFindByID(3, u.UserID)
+	assert.NoError(t, err)
+	assert.True(t, task.Blocked)
+}
+
+func TestAppUseCase_TasksEdit_StrictDependencies(t *testing.T) {
+	s := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.StrictDependencies = true
+	uc := usecase.NewAppUseCase(config, s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	uc.TasksCreate(t1)
+	uc.TasksCreate(t2)
+	uc.DependenciesCreate(&entity.Dependency{TaskID: t2.TaskID, BlockerID: t1.TaskID})
+
+	done := *t2
+	done.Done = true
+	_, err := uc.TasksEdit(&done)
+	assert.EqualError(t, err, usecase.ErrTaskBlocked.Error())
+
+	t1.Done = true
+	uc.TasksEdit(t1)
+	_, err = uc.TasksEdit(&done)
+	assert.NoError(t, err)
+}
+
+func TestAppUseCase_ListsMove(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	for n := 1; n <= 3; n++ {
+		l := entity.TestList(t)
+		l.ListTitle = fmt.Sprintf("TEST TITLE %d", n)
+		l.UserID = u.UserID
+		uc.ListsCreate(l)
+	}
+
+	l, _ := uc.ListsFindByID(3, u.UserID)
+	_, err := uc.ListsMove(l, 0, 1)
+	assert.NoError(t, err)
+
+	lists, _ := uc.ListsFindByUser(u.UserID, false)
+	assert.Equal(t, 3, lists[0].ListID)
+	assert.Equal(t, 1, lists[1].ListID)
+	assert.Equal(t, 2, lists[2].ListID)
+
+	_, err = uc.ListsMove(l, 1, 2)
+	assert.EqualError(t, err, usecase.ErrInvalidMove.Error())
+}
+
+func TestAppUseCase_TasksMove(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
 	u := entity.TestUser(t)
 	l1 := entity.TestList(t)
 	l2 := entity.TestList(t)
@@ -133,13 +496,973 @@ func TestAppUseCase_ListsFindByUser(t *testing.T) {
 	uc.UsersCreate(u)
 	l1.UserID = u.UserID
 	l2.UserID = u.UserID
+	uc.ListsCreate(l1)
+	uc.ListsCreate(l2)
+
+	for n := 1; n <= 4; n++ {
+		task := entity.TestTask(t)
+		task.TaskTitle = fmt.Sprintf("test task %d", n)
+		task.ListID = l1.ListID
+		if n == 4 {
+			task.ListID = l2.ListID
+		}
+		uc.TasksCreate(task)
+	}
+
+	order := func(listID int) []int {
+		tasks, _ := uc.TasksFindByList(listID)
+		ids := make([]int, len(tasks))
+		for n, task := range tasks {
+			ids[n] = task.TaskID
+		}
+		return ids
+	}
+
+	testCases := []struct {
+		name     string
+		taskID   int
+		listID   int
+		afterID  int
+		beforeID int
+		expected map[int][]int
+		isValid  bool
+	}{
+		{
+			name:     "after",
+			taskID:   1,
+			afterID:  2,
+			expected: map[int][]int{l1.ListID: {2, 1, 3}},
+			isValid:  true,
+		},
+		{
+			name:     "before",
+			taskID:   3,
+			beforeID: 2,
+			expected: map[int][]int{l1.ListID: {3, 2, 1}},
+			isValid:  true,
+		},
+		{
+			name:     "to the end",
+			taskID:   3,
+			expected: map[int][]int{l1.ListID: {2, 1, 3}},
+			isValid:  true,
+		},
+		{
+			name:     "to another list",
+			taskID:   2,
+			listID:   l2.ListID,
+			beforeID: 4,
+			expected: map[int][]int{l1.ListID: {1, 3}, l2.ListID: {2, 4}},
+			isValid:  true,
+		},
+		{
+			name:    "anchor from another list",
+			taskID:  1,
+			afterID: 4,
+			isValid: false,
+		},
+		{
+			name:     "both anchors",
+			taskID:   1,
+			afterID:  3,
+			beforeID: 3,
+			isValid:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task, _ := uc.TasksFindByID(tc.taskID, u.UserID)
+			_, err := uc.TasksMove(task, tc.listID, tc.afterID, tc.beforeID)
+			if tc.isValid {
+				assert.NoError(t, err)
+				for listID, ids := range tc.expected {
+					assert.Equal(t, ids, order(listID))
+				}
+			} else {
+				assert.EqualError(t, err, usecase.ErrInvalidMove.Error())
+			}
+		})
+	}
+}
+
+func TestAppUseCase_LabelsCreate(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	l := entity.TestLabel(t)
+	l.UserID = u.UserID
+	assert.NoError(t, uc.LabelsCreate(l))
+
+	labels, err := uc.LabelsFindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+}
+
+func TestAppUseCase_TasksFindByFilter(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	lb1 := entity.TestLabel(t)
+	lb2 := entity.TestLabel(t)
+	lb2.LabelTitle = "later"
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t2.ListID = l.ListID
+	uc.TasksCreate(t1)
+	uc.TasksCreate(t2)
+	lb1.UserID = u.UserID
+	lb2.UserID = u.UserID
+	uc.LabelsCreate(lb1)
+	uc.LabelsCreate(lb2)
+	uc.LabelsAttach(t1.TaskID, lb1.LabelID)
+	uc.LabelsAttach(t1.TaskID, lb2.LabelID)
+	uc.LabelsAttach(t2.TaskID, lb2.LabelID)
+
+	tasks, err := uc.TasksFindByFilter(&entity.TaskFilter{ListID: l.ListID, LabelIDs: []int{lb1.LabelID, lb2.LabelID}})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	tasks, err = uc.TasksFindByFilter(&entity.TaskFilter{ListID: l.ListID, LabelIDs: []int{lb1.LabelID, lb2.LabelID}, AllLabels: true})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, t1.TaskID, tasks[0].TaskID)
+	assert.Len(t, tasks[0].Labels, 2)
+}
+
+func TestAppUseCase_ViewsFindByKey(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+	v := entity.TestView(t)
+	v.UserID = u.UserID
+	uc.ViewsCreate(v)
+
+	found, err := uc.ViewsFindByKey(fmt.Sprint(v.ViewID), u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, v.ViewTitle, found.ViewTitle)
+
+	found, err = uc.ViewsFindByKey("overdue", u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Overdue", found.ViewTitle)
+
+	_, err = uc.ViewsFindByKey("someday", u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	views, err := uc.ViewsFindByUser(u.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, views, len(entity.BuiltinViews())+1)
+}
+
+func TestAppUseCase_ViewsTasks(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	t1.ListID = l.ListID
+	t1.Deadline = &entity.TimeISO{Time: time.Now().Add(-time.Hour)}
+	t2.ListID = l.ListID
+	t2.Deadline = &entity.TimeISO{Time: time.Now().AddDate(0, 0, 3)}
+	uc.TasksCreate(t1)
+	uc.TasksCreate(t2)
+
+	overdue, _ := entity.BuiltinView("overdue")
+	tasks, err := uc.ViewsTasks(overdue, u)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, t1.TaskID, tasks[0].TaskID)
+
+	upcoming, _ := entity.BuiltinView("upcoming")
+	tasks, err = uc.ViewsTasks(upcoming, u)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, t2.TaskID, tasks[0].TaskID)
+	assert.Nil(t, upcoming.Filter.DueAfter)
+}
+
+func TestAppUseCase_ListsCreateFromTemplate(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	t1 := entity.TestTask(t)
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.Deadline = &entity.TimeISO{Time: t1.Deadline.Time.AddDate(0, 0, 2)}
+	uc.UsersCreate(u)
+	l1.UserID = u.UserID
+	uc.ListsCreate(l1)
+	t1.ListID = l1.ListID
+	t2.ListID = l1.ListID
+	uc.TasksCreate(t1)
+	uc.TasksCreate(t2)
+	i := entity.TestItem(t)
+	i.TaskID = t2.TaskID
+	uc.ItemsCreate(i)
+
+	tpl := &entity.Template{TemplateTitle: "release", UserID: u.UserID}
+	assert.NoError(t, uc.TemplatesCreate(tpl, l1, time.UTC))
+	assert.Len(t, tpl.Tasks, 2)
+	assert.Equal(t, 2, tpl.Tasks[1].DueDay)
+
+	l2 := &entity.List{ListTitle: "release 2", UserID: u.UserID}
+	start, _ := entity.ParseDate("2027-01-10")
+	assert.NoError(t, uc.ListsCreateFromTemplate(l2, tpl, start, time.UTC))
+
+	tasks, err := uc.TasksFindByList(l2.ListID)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, time.Date(2027, 1, 10, 12, 0, 0, 0, time.UTC), tasks[0].Deadline.Time)
+	assert.Equal(t, time.Date(2027, 1, 12, 12, 0, 0, 0, time.UTC), tasks[1].Deadline.Time)
+	assert.Equal(t, entity.Progress{Done: 0, Total: 1}, tasks[1].Progress)
+
+	l3 := &entity.List{ListTitle: l1.ListTitle, UserID: u.UserID}
+	assert.Error(t, uc.ListsCreateFromTemplate(l3, tpl, start, time.UTC))
+}
+
+func TestAppUseCase_ListsArchive(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	l1 := entity.TestList(t)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l1.UserID = u.UserID
+	l2.UserID = u.UserID
+	uc.ListsCreate(l1)
+	uc.ListsCreate(l2)
+	task.ListID = l1.ListID
+	uc.TasksCreate(task)
+
+	l1, err := uc.ListsArchive(l1)
+	assert.NoError(t, err)
+	assert.NotNil(t, l1.ArchivedAt)
+
+	lists, err := uc.ListsFindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+	assert.Equal(t, l2.ListID, lists[0].ListID)
+
+	lists, err = uc.ListsFindByUser(u.UserID, true)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+	assert.Equal(t, l1.ListID, lists[0].ListID)
+
+	t2 := entity.TestTask(t)
+	t2.TaskTitle = "test task 2"
+	t2.ListID = l1.ListID
+	assert.EqualError(t, uc.TasksCreate(t2), usecase.ErrListArchived.Error())
+
+	_, err = uc.TasksEdit(task)
+	assert.EqualError(t, err, usecase.ErrListArchived.Error())
+
+	assert.EqualError(t, uc.ItemsCreate(&entity.Item{ItemTitle: "test item", TaskID: task.TaskID}), usecase.ErrListArchived.Error())
+	assert.EqualError(t, uc.LabelsAttach(task.TaskID, 1), usecase.ErrListArchived.Error())
+	assert.EqualError(t, uc.TasksDelete(task), usecase.ErrListArchived.Error())
+
+	_, err = uc.TasksMove(task, l2.ListID, 0, 0)
+	assert.EqualError(t, err, usecase.ErrListArchived.Error())
+
+	l1, err = uc.ListsUnarchive(l1)
+	assert.NoError(t, err)
+	assert.Nil(t, l1.ArchivedAt)
+
+	lists, err = uc.ListsFindByUser(u.UserID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 2)
+	assert.Equal(t, l1.ListID, lists[1].ListID)
+
+	assert.NoError(t, uc.TasksCreate(t2))
+}
+
+type testNotifier struct {
+	notifications []*entity.Notification
+	err           error
+}
+
+func (n *testNotifier) Notify(notification *entity.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestAppUseCase_RemindersSend(t *testing.T) {
+	s := testrepository.TestStore(t)
+	n := &testNotifier{}
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithNotifier(n)
+	u := entity.TestUser(t)
+	l := entity.TestList(t)
+	task := entity.TestTask(t)
+	uc.UsersCreate(u)
+	l.UserID = u.UserID
+	uc.ListsCreate(l)
+	task.ListID = l.ListID
+	uc.TasksCreate(task)
+
+	r1 := entity.TestReminder(t)
+	r1.TaskID = task.TaskID
+	assert.NoError(t, uc.RemindersCreate(r1))
+	r2 := entity.TestReminder(t)
+	r2.TaskID = task.TaskID
+	assert.NoError(t, uc.RemindersCreate(r2))
+
+	now := task.Deadline.Time
+	n.err = errors.New("notifier is down")
+	sent, err := uc.RemindersSend(now, 1)
+	assert.EqualError(t, err, n.err.Error())
+	assert.Equal(t, 0, sent)
+
+	// the failed reminder waits for its retry instead of blocking the next one
+	n.err = nil
+	sent, err = uc.RemindersSend(now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, n.notifications, 1)
+	assert.Equal(t, r2.ReminderID, n.notifications[0].Reminder.ReminderID)
+	assert.Equal(t, u.Email, n.notifications[0].User.Email)
+	assert.Equal(t, task.TaskTitle, n.notifications[0].Task.TaskTitle)
+
+	sent, err = uc.RemindersSend(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	sent, err = uc.RemindersSend(now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, r1.ReminderID, n.notifications[1].Reminder.ReminderID)
+
+	sent, err = uc.RemindersSend(now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	reminders, err := uc.RemindersFindByTask(task.TaskID)
+	assert.NoError(t, err)
+	assert.NotNil(t, reminders[0].SentAt)
+	assert.NotNil(t, reminders[1].SentAt)
+}
+
+func TestAppUseCase_PasswordResets(t *testing.T) {
+	s := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithMailer(mailer)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	assert.NoError(t, uc.PasswordResetsCreate("unknown@example.org"))
+	assert.Empty(t, sender.Messages)
+
+	assert.NoError(t, uc.PasswordResetsCreate(u.Email))
+	assert.Len(t, sender.Messages, 1)
+	assert.Equal(t, u.Email, sender.Messages[0].To)
+
+	token := sender.LastToken(t)
+
+	testCases := []struct {
+		name     string
+		token    string
+		password string
+		isValid  bool
+		err      error
+	}{
+		{
+			name:     "unknown token",
+			token:    "unknown",
+			password: "new password",
+			err:      usecase.ErrInvalidResetToken,
+		},
+		{
+			name:     "short password",
+			token:    token,
+			password: "short",
+		},
+		{
+			name:     "valid",
+			token:    token,
+			password: "new password",
+			isValid:  true,
+		},
+		{
+			name:     "used token",
+			token:    token,
+			password: "new password",
+			err:      usecase.ErrInvalidResetToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := uc.PasswordResetsConfirm(tc.token, tc.password)
+			switch {
+			case tc.isValid:
+				assert.NoError(t, err)
+			case tc.err != nil:
+				assert.EqualError(t, err, tc.err.Error())
+			default:
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	u, _ = uc.UsersFindByID(u.UserID)
+	assert.True(t, u.ComparePassword("new password"))
+	assert.Equal(t, 1, u.TokenVersion)
+}
+
+func TestAppUseCase_PasswordResetsConfirm_Expired(t *testing.T) {
+	s := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	config := usecase.NewConfig()
+	config.PasswordResetTTL = -time.Minute
+	uc := usecase.NewAppUseCase(config, s).WithMailer(mailer)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	uc.PasswordResetsCreate(u.Email)
+	err := uc.PasswordResetsConfirm(sender.LastToken(t), "new password")
+	assert.EqualError(t, err, usecase.ErrInvalidResetToken.Error())
+}
+
+func TestAppUseCase_EmailVerifications(t *testing.T) {
+	s := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithMailer(mailer)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	assert.NoError(t, uc.EmailVerificationsSend(u))
+	assert.Len(t, sender.Messages, 1)
+	assert.EqualError(t, uc.EmailVerificationsSend(u), usecase.ErrVerificationTooSoon.Error())
+	assert.Len(t, sender.Messages, 1)
+
+	err := uc.EmailVerificationsConfirm("unknown")
+	assert.EqualError(t, err, usecase.ErrInvalidVerificationToken.Error())
+
+	assert.NoError(t, uc.EmailVerificationsConfirm(sender.LastToken(t)))
+	u, _ = uc.UsersFindByID(u.UserID)
+	assert.True(t, u.Verified())
+
+	assert.EqualError(t, uc.EmailVerificationsSend(u), usecase.ErrEmailVerified.Error())
+}
+
+func TestAppUseCase_EmailVerificationsConfirm_Expired(t *testing.T) {
+	s := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	config := usecase.NewConfig()
+	config.EmailVerificationTTL = -time.Minute
+	uc := usecase.NewAppUseCase(config, s).WithMailer(mailer)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	uc.EmailVerificationsSend(u)
+	err := uc.EmailVerificationsConfirm(sender.LastToken(t))
+	assert.EqualError(t, err, usecase.ErrInvalidVerificationToken.Error())
+}
+
+func TestAppUseCase_UsersFindOrCreateVerified(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	linked, err := uc.UsersFindOrCreateVerified(u.Email)
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, linked.UserID)
+	assert.True(t, linked.Verified())
+
+	created, err := uc.UsersFindOrCreateVerified("new@example.org")
+	assert.NoError(t, err)
+	assert.NotEqual(t, u.UserID, created.UserID)
+	assert.True(t, created.Verified())
+	assert.Empty(t, created.Password)
+}
+
+func TestAppUseCase_Login_Rehash(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	entity.SetPasswordHasher(&entity.Argon2idHasher{Memory: 64, Time: 1, Threads: 1})
+	defer entity.SetPasswordHasher(&entity.BcryptHasher{Cost: bcrypt.MinCost})
+
+	_, err := uc.Login(u.Email, "password", "10.0.0.1")
+	assert.NoError(t, err)
+
+	found, _ := uc.UsersFindByID(u.UserID)
+	assert.True(t, strings.HasPrefix(found.EncryptedPassword, "$argon2id$"))
+	assert.False(t, found.NeedsRehash())
+
+	_, err = uc.Login(u.Email, "password", "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func(*usecase.Config)
+		isValid bool
+	}{
+		{
+			name:    "bcrypt",
+			config:  func(c *usecase.Config) {},
+			isValid: true,
+		},
+		{
+			name:    "argon2id",
+			config:  func(c *usecase.Config) { c.PasswordHasher = usecase.HasherArgon2id },
+			isValid: true,
+		},
+		{
+			name:    "unknown",
+			config:  func(c *usecase.Config) { c.PasswordHasher = "md5" },
+			isValid: false,
+		},
+		{
+			name:    "low bcrypt cost",
+			config:  func(c *usecase.Config) { c.BcryptCost = 2 },
+			isValid: false,
+		},
+		{
+			name: "argon2id without threads",
+			config: func(c *usecase.Config) {
+				c.PasswordHasher = usecase.HasherArgon2id
+				c.Argon2Threads = 0
+			},
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := usecase.NewConfig()
+			tc.config(config)
+
+			_, err := usecase.NewPasswordHasher(config)
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewMFAKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  func(*usecase.Config)
+		isValid bool
+	}{
+		{
+			name:    "key",
+			config:  func(c *usecase.Config) { c.MFAKey = base64.StdEncoding.EncodeToString(entity.TestMFAKey(t)) },
+			isValid: true,
+		},
+		{
+			name: "dev without key",
+			config: func(c *usecase.Config) {
+				c.MFAKey = ""
+				c.Dev = true
+			},
+			isValid: true,
+		},
+		{
+			name:    "without key",
+			config:  func(c *usecase.Config) { c.MFAKey = "" },
+			isValid: false,
+		},
+		{
+			name:    "short key",
+			config:  func(c *usecase.Config) { c.MFAKey = base64.StdEncoding.EncodeToString(make([]byte, 16)) },
+			isValid: false,
+		},
+		{
+			name:    "not base64",
+			config:  func(c *usecase.Config) { c.MFAKey = "not base64!" },
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := usecase.NewConfig()
+			tc.config(config)
+
+			key, err := usecase.NewMFAKey(config)
+			if tc.isValid {
+				assert.NoError(t, err)
+				assert.Len(t, key, 32)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestAppUseCase_UsersEditPassword(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	assert.EqualError(t, uc.UsersEditPassword(u, "wrong", "new password"), usecase.ErrIncorrectPassword.Error())
+	assert.Error(t, uc.UsersEditPassword(u, "password", "short"))
+
+	assert.NoError(t, uc.UsersEditPassword(u, "password", "new password"))
+	assert.Equal(t, 1, u.TokenVersion)
+
+	found, err := uc.Login(u.Email, "new password", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, found.UserID)
+}
+
+func TestAppUseCase_UsersEditEmail(t *testing.T) {
+	s := testrepository.TestStore(t)
+	mailer, sender := email.TestMailer(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithMailer(mailer)
+	u1 := entity.TestUser(t)
+	uc.UsersCreate(u1)
+	u2 := entity.TestUser(t)
+	u2.Email = "other@example.org"
+	uc.UsersCreate(u2)
+
+	// a verification sent to the old email can't confirm the new one
+	uc.EmailVerificationsSend(u1)
+	oldToken := sender.LastToken(t)
+
+	err := uc.UsersEditEmail(u1, "wrong", "new@example.org")
+	assert.EqualError(t, err, usecase.ErrIncorrectPassword.Error())
+
+	err = uc.UsersEditEmail(u1, "password", u2.Email)
+	assert.EqualError(t, err, usecase.ErrEmailTaken.Error())
+
+	assert.NoError(t, uc.UsersEditEmail(u1, "password", "new@example.org"))
+	assert.Equal(t, "new@example.org", u1.Email)
+	assert.False(t, u1.Verified())
+
+	err = uc.EmailVerificationsConfirm(oldToken)
+	assert.EqualError(t, err, usecase.ErrInvalidVerificationToken.Error())
+	assert.NoError(t, uc.EmailVerificationsSend(u1))
+}
 
-	_, err := uc.ListsFindByUser(u.UserID)
+func TestAppUseCase_UsersDelete(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	assert.EqualError(t, uc.UsersDelete(u, "wrong"), usecase.ErrIncorrectPassword.Error())
+	assert.NoError(t, uc.UsersDelete(u, "password"))
+
+	_, err := uc.UsersFindByID(u.UserID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+}
+
+func TestAppUseCase_UsersDelete_Cascade(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithMFAKey(entity.TestMFAKey(t))
+
+	// createData gives the user one of everything and returns the task
+	createData := func(u *entity.User) *entity.Task {
+		uc.UsersCreate(u)
+
+		l := entity.TestList(t)
+		l.UserID = u.UserID
+		uc.ListsCreate(l)
+
+		task := entity.TestTask(t)
+		task.ListID = l.ListID
+		uc.TasksCreate(task)
+
+		i := entity.TestItem(t)
+		i.TaskID = task.TaskID
+		uc.ItemsCreate(i)
+
+		rm := &entity.Reminder{TaskID: task.TaskID, RemindAt: &entity.TimeISO{Time: time.Now().Add(time.Hour)}}
+		uc.RemindersCreate(rm)
+
+		label := entity.TestLabel(t)
+		label.UserID = u.UserID
+		uc.LabelsCreate(label)
+		uc.LabelsAttach(task.TaskID, label.LabelID)
+
+		v := entity.TestView(t)
+		v.UserID = u.UserID
+		uc.ViewsCreate(v)
+
+		tmpl := entity.TestTemplate(t)
+		tmpl.UserID = u.UserID
+		uc.TemplatesCreate(tmpl, l, time.UTC)
+
+		k := entity.TestAPIKey(t)
+		k.UserID = u.UserID
+		uc.APIKeysCreate(k)
+
+		uc.MFAEnroll(u)
+		return task
+	}
+
+	u := entity.TestUser(t)
+	task := createData(u)
+	other := entity.TestUser(t)
+	other.Email = "other@example.org"
+	otherTask := createData(other)
+
+	assert.NoError(t, uc.UsersDelete(u, "password"))
+
+	usage, err := uc.UsersUsage(u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Usage{}, usage)
+
+	_, err = s.Task().FindByID(task.TaskID)
+	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
+
+	items, _ := s.Item().FindByTask(task.TaskID)
+	assert.Empty(t, items)
+
+	reminders, _ := s.Reminder().FindByTask(task.TaskID)
+	assert.Empty(t, reminders)
+
+	labels, _ := s.Label().FindByTasks(task.TaskID)
+	assert.Empty(t, labels[task.TaskID])
+
+	_, err = s.MFA().FindByUser(u.UserID)
 	assert.EqualError(t, err, store.ErrRecordNotFound.Error())
 
+	// the data of other users stays
+	usage, err = uc.UsersUsage(other.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Usage{Lists: 1, Tasks: 1, Labels: 1, Views: 1, Templates: 1, APIKeys: 1}, usage)
+
+	items, _ = s.Item().FindByTask(otherTask.TaskID)
+	assert.Len(t, items, 1)
+
+	reminders, _ = s.Reminder().FindByTask(otherTask.TaskID)
+	assert.Len(t, reminders, 1)
+
+	labels, _ = s.Label().FindByTasks(otherTask.TaskID)
+	assert.Len(t, labels[otherTask.TaskID], 1)
+
+	_, err = s.MFA().FindByUser(other.UserID)
+	assert.NoError(t, err)
+}
+
+func TestAppUseCase_UsersUsage(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	l1 := entity.TestList(t)
+	l1.UserID = u.UserID
 	uc.ListsCreate(l1)
+	l2 := entity.TestList(t)
+	l2.ListTitle = "TEST TITLE 2"
+	l2.UserID = u.UserID
 	uc.ListsCreate(l2)
-	lists, err := uc.ListsFindByUser(u.UserID)
+
+	for _, listID := range []int{l1.ListID, l2.ListID} {
+		task := entity.TestTask(t)
+		task.ListID = listID
+		uc.TasksCreate(task)
+	}
+	uc.ListsArchive(l2)
+
+	label := entity.TestLabel(t)
+	label.UserID = u.UserID
+	uc.LabelsCreate(label)
+
+	usage, err := uc.UsersUsage(u.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Usage{Lists: 2, Tasks: 2, Labels: 1}, usage)
+}
+
+func TestAppUseCase_Login(t *testing.T) {
+	s := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.LoginFreeAttempts = 2
+	config.LoginIPFreeAttempts = 4
+	uc := usecase.NewAppUseCase(config, s)
+	u := entity.TestUser(t)
+	password := u.Password
+	uc.UsersCreate(u)
+
+	_, err := uc.Login(u.Email, "wrong", "10.0.0.1")
+	assert.EqualError(t, err, usecase.ErrIncorrectEmailOrPassword.Error())
+
+	// a success resets the failures of the email
+	found, err := uc.Login(u.Email, password, "10.0.0.1")
 	assert.NoError(t, err)
-	assert.NotNil(t, lists)
+	assert.Equal(t, u.UserID, found.UserID)
+
+	_, err = uc.Login(u.Email, "wrong", "10.0.0.1")
+	assert.EqualError(t, err, usecase.ErrIncorrectEmailOrPassword.Error())
+	_, err = uc.Login(u.Email, "wrong", "10.0.0.2")
+	assert.EqualError(t, err, usecase.ErrIncorrectEmailOrPassword.Error())
+
+	var locked *usecase.LoginLockedError
+	_, err = uc.Login(u.Email, password, "10.0.0.3")
+	assert.ErrorAs(t, err, &locked)
+	assert.InDelta(t, time.Minute, locked.RetryAfter, float64(time.Second))
+
+	_, err = uc.Login(strings.ToUpper(u.Email), password, "10.0.0.3")
+	assert.ErrorAs(t, err, &locked)
+
+	// unknown emails fail the same way and count for the IP address
+	_, err = uc.Login("unknown@example.org", "wrong", "10.0.0.1")
+	assert.EqualError(t, err, usecase.ErrIncorrectEmailOrPassword.Error())
+	_, err = uc.Login("another@example.org", "wrong", "10.0.0.1")
+	assert.EqualError(t, err, usecase.ErrIncorrectEmailOrPassword.Error())
+	_, err = uc.Login("third@example.org", "wrong", "10.0.0.1")
+	assert.ErrorAs(t, err, &locked)
+}
+
+func TestAppUseCase_LoginMFA(t *testing.T) {
+	s := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.MFAFreeAttempts = 2
+	uc := usecase.NewAppUseCase(config, s).WithMFAKey(entity.TestMFAKey(t))
+	u := entity.TestUser(t)
+	password := u.Password
+	uc.UsersCreate(u)
+	version := u.TokenVersion
+
+	secret, _, _ := uc.MFAEnroll(u)
+	step := entity.TOTPStep(time.Now())
+	code, _ := entity.TOTPCode(secret, step)
+	uc.MFAConfirm(u, code)
+
+	next, _ := entity.TOTPCode(secret, step+1)
+	wrong, _ := entity.TOTPCode(secret, step-5)
+
+	assert.NoError(t, uc.LoginMFA(u, next, "10.0.0.1"))
+
+	err := uc.LoginMFA(u, wrong, "10.0.0.1")
+	assert.EqualError(t, err, usecase.ErrInvalidMFACode.Error())
+	found, _ := uc.UsersFindByID(u.UserID)
+	assert.Equal(t, version, found.TokenVersion)
+
+	// a correct password doesn't reset the failures of the second factor
+	_, err = uc.Login(u.Email, password, "10.0.0.1")
+	assert.NoError(t, err)
+
+	err = uc.LoginMFA(u, wrong, "10.0.0.2")
+	assert.EqualError(t, err, usecase.ErrInvalidMFACode.Error())
+	found, _ = uc.UsersFindByID(u.UserID)
+	assert.Greater(t, found.TokenVersion, version)
+
+	var locked *usecase.LoginLockedError
+	err = uc.LoginMFA(found, next, "10.0.0.2")
+	assert.ErrorAs(t, err, &locked)
+	assert.InDelta(t, time.Minute, locked.RetryAfter, float64(time.Second))
+}
+
+func TestAppUseCase_ListsCreate_RequireVerifiedEmail(t *testing.T) {
+	s := testrepository.TestStore(t)
+	config := usecase.NewConfig()
+	config.RequireVerifiedEmail = true
+	uc := usecase.NewAppUseCase(config, s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	l := entity.TestList(t)
+	l.UserID = u.UserID
+	assert.EqualError(t, uc.ListsCreate(l), usecase.ErrEmailNotVerified.Error())
+
+	s.User().Verify(u)
+	assert.NoError(t, uc.ListsCreate(l))
+}
+
+func TestAppUseCase_MFA(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s).WithMFAKey(entity.TestMFAKey(t))
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	_, err := uc.MFAConfirm(u, "000000")
+	assert.EqualError(t, err, usecase.ErrMFANotEnrolled.Error())
+
+	secret, uri, err := uc.MFAEnroll(u)
+	assert.NoError(t, err)
+	assert.Contains(t, uri, secret)
+
+	enabled, _ := uc.MFAEnabled(u.UserID)
+	assert.False(t, enabled)
+
+	step := entity.TOTPStep(time.Now())
+	code, _ := entity.TOTPCode(secret, step)
+	wrong, _ := entity.TOTPCode(secret, step-5)
+
+	_, err = uc.MFAConfirm(u, wrong)
+	assert.EqualError(t, err, usecase.ErrInvalidMFACode.Error())
+
+	recoveryCodes, err := uc.MFAConfirm(u, code)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, recoveryCodes)
+
+	enabled, _ = uc.MFAEnabled(u.UserID)
+	assert.True(t, enabled)
+
+	_, _, err = uc.MFAEnroll(u)
+	assert.EqualError(t, err, usecase.ErrMFAEnabled.Error())
+
+	// a code is accepted only once
+	assert.EqualError(t, uc.MFAVerify(u.UserID, code), usecase.ErrInvalidMFACode.Error())
+
+	next, _ := entity.TOTPCode(secret, step+1)
+	assert.NoError(t, uc.MFAVerify(u.UserID, next))
+
+	assert.NoError(t, uc.MFAVerify(u.UserID, recoveryCodes[0]))
+	assert.EqualError(t, uc.MFAVerify(u.UserID, recoveryCodes[0]), usecase.ErrInvalidMFACode.Error())
+
+	assert.EqualError(t, uc.MFADisable(u, wrong), usecase.ErrInvalidMFACode.Error())
+	assert.NoError(t, uc.MFADisable(u, recoveryCodes[1]))
+
+	enabled, _ = uc.MFAEnabled(u.UserID)
+	assert.False(t, enabled)
+}
+
+func TestAppUseCase_APIKeysAuthenticate(t *testing.T) {
+	s := testrepository.TestStore(t)
+	uc := usecase.NewAppUseCase(usecase.NewConfig(), s)
+	u := entity.TestUser(t)
+	uc.UsersCreate(u)
+
+	k := entity.TestAPIKey(t)
+	k.UserID = u.UserID
+	key, err := uc.APIKeysCreate(k)
+	assert.NoError(t, err)
+	assert.NotContains(t, k.KeyHash, key)
+
+	found, foundKey, err := uc.APIKeysAuthenticate(key)
+	assert.NoError(t, err)
+	assert.Equal(t, u.UserID, found.UserID)
+	assert.Equal(t, k.APIKeyID, foundKey.APIKeyID)
+	assert.NotNil(t, foundKey.LastUsedAt)
+
+	_, _, err = uc.APIKeysAuthenticate(entity.APIKeyPrefix + "unknown")
+	assert.EqualError(t, err, usecase.ErrInvalidAPIKey.Error())
+
+	k.ExpiresAt = &entity.TimeISO{Time: time.Now().Add(-time.Minute)}
+	_, _, err = uc.APIKeysAuthenticate(key)
+	assert.EqualError(t, err, usecase.ErrInvalidAPIKey.Error())
 }
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN timezone;
\ No newline at end of file
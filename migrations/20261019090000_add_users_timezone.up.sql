@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN timezone VARCHAR NOT NULL DEFAULT 'UTC';
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE tasks DROP CONSTRAINT tasks_deadline_check;
+UPDATE tasks SET deadline = due_date::TIMESTAMP AT TIME ZONE 'UTC' WHERE deadline IS NULL;
+ALTER TABLE tasks DROP COLUMN due_date;
+ALTER TABLE tasks ALTER COLUMN deadline SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN deadline TYPE TIMESTAMP USING deadline AT TIME ZONE 'UTC';
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- existing deadlines were written without an offset, treat them as UTC
+ALTER TABLE tasks ALTER COLUMN deadline TYPE TIMESTAMPTZ USING deadline AT TIME ZONE 'UTC';
+ALTER TABLE tasks ALTER COLUMN deadline DROP NOT NULL;
+ALTER TABLE tasks ADD COLUMN due_date DATE;
+ALTER TABLE tasks ADD CONSTRAINT tasks_deadline_check CHECK (num_nonnulls(deadline, due_date) = 1);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE items;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE items (
+    item_id BIGSERIAL PRIMARY KEY,
+    item_title VARCHAR NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL,
+    task_id BIGINT REFERENCES tasks ON DELETE CASCADE
+);
+
+CREATE INDEX items_task_id_idx ON items (task_id, position);
\ No newline at end of file
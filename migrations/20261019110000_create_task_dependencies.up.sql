@@ -0,0 +1,6 @@
+CREATE TABLE task_dependencies (
+    task_id BIGINT REFERENCES tasks ON DELETE CASCADE,
+    blocker_id BIGINT REFERENCES tasks ON DELETE CASCADE,
+    PRIMARY KEY(task_id, blocker_id),
+    CHECK (task_id <> blocker_id)
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN position;
+ALTER TABLE lists DROP COLUMN position;
\ No newline at end of file
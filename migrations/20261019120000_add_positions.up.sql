@@ -0,0 +1,16 @@
+-- fractional keys compared byte-wise, backfilled in creation order
+ALTER TABLE lists ADD COLUMN position VARCHAR COLLATE "C" NOT NULL DEFAULT '';
+UPDATE lists SET position = p.position FROM (
+    SELECT list_id, lpad(to_hex(row_number() OVER (PARTITION BY user_id ORDER BY list_id)), 8, '0') || 'i' AS position
+    FROM lists
+) p WHERE lists.list_id = p.list_id;
+ALTER TABLE lists ALTER COLUMN position DROP DEFAULT;
+CREATE INDEX lists_user_id_position_idx ON lists (user_id, position);
+
+ALTER TABLE tasks ADD COLUMN position VARCHAR COLLATE "C" NOT NULL DEFAULT '';
+UPDATE tasks SET position = p.position FROM (
+    SELECT task_id, lpad(to_hex(row_number() OVER (PARTITION BY list_id ORDER BY task_id)), 8, '0') || 'i' AS position
+    FROM tasks
+) p WHERE tasks.task_id = p.task_id;
+ALTER TABLE tasks ALTER COLUMN position DROP DEFAULT;
+CREATE INDEX tasks_list_id_position_idx ON tasks (list_id, position);
\ No newline at end of file
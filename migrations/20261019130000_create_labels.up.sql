@@ -0,0 +1,15 @@
+CREATE TABLE labels (
+    label_id BIGSERIAL PRIMARY KEY,
+    label_title VARCHAR NOT NULL,
+    color VARCHAR NOT NULL,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    UNIQUE(label_title, user_id)
+);
+
+CREATE TABLE task_labels (
+    task_id BIGINT REFERENCES tasks ON DELETE CASCADE,
+    label_id BIGINT REFERENCES labels ON DELETE CASCADE,
+    PRIMARY KEY(task_id, label_id)
+);
+
+CREATE INDEX task_labels_label_id_idx ON task_labels(label_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX tasks_list_id_priority_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN priority,
+    DROP COLUMN estimate;
\ No newline at end of file
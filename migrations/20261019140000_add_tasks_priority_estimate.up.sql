@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 4),
+    ADD COLUMN estimate INTEGER CHECK (estimate >= 0);
+
+CREATE INDEX tasks_list_id_priority_idx ON tasks(list_id, priority DESC);
\ No newline at end of file
@@ -0,0 +1,6 @@
+UPDATE lists SET list_title = upper(list_title);
+
+ALTER TABLE lists
+    DROP CONSTRAINT lists_list_title_normalized_user_id_key,
+    DROP COLUMN list_title_normalized,
+    ADD CONSTRAINT lists_list_title_user_id_key UNIQUE(list_title, user_id);
\ No newline at end of file
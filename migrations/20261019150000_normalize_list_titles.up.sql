@@ -0,0 +1,8 @@
+ALTER TABLE lists ADD COLUMN list_title_normalized VARCHAR;
+
+UPDATE lists SET list_title_normalized = lower(list_title);
+
+ALTER TABLE lists
+    ALTER COLUMN list_title_normalized SET NOT NULL,
+    DROP CONSTRAINT lists_list_title_user_id_key,
+    ADD CONSTRAINT lists_list_title_normalized_user_id_key UNIQUE(list_title_normalized, user_id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE views (
+    view_id BIGSERIAL PRIMARY KEY,
+    view_title VARCHAR NOT NULL,
+    filter JSONB NOT NULL,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    UNIQUE(view_title, user_id)
+);
\ No newline at end of file
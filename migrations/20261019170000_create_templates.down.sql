@@ -0,0 +1 @@
+DROP TABLE templates;
\ No newline at end of file
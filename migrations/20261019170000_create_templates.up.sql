@@ -0,0 +1,7 @@
+CREATE TABLE templates (
+    template_id BIGSERIAL PRIMARY KEY,
+    template_title VARCHAR NOT NULL,
+    tasks JSONB NOT NULL,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    UNIQUE(template_title, user_id)
+);
\ No newline at end of file
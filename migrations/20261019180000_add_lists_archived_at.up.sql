@@ -0,0 +1 @@
+ALTER TABLE lists ADD COLUMN archived_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE reminders (
+    reminder_id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT REFERENCES tasks ON DELETE CASCADE,
+    remind_at TIMESTAMPTZ,
+    offset_minutes INTEGER CHECK (offset_minutes >= 0),
+    sent_at TIMESTAMPTZ,
+    CHECK (num_nonnulls(remind_at, offset_minutes) = 1)
+);
+
+CREATE INDEX reminders_pending_idx ON reminders(task_id) WHERE sent_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE password_resets (
+    reset_id BIGSERIAL PRIMARY KEY,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE email_verifications;
+
+ALTER TABLE users DROP COLUMN email_verified_at;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- accounts created before verification existed keep working
+UPDATE users SET email_verified_at = NOW();
+
+CREATE TABLE email_verifications (
+    verification_id BIGSERIAL PRIMARY KEY,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX email_verifications_user_id_idx ON email_verifications (user_id, created_at);
\ No newline at end of file
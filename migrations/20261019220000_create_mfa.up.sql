@@ -0,0 +1,14 @@
+CREATE TABLE user_mfa (
+    user_id BIGINT PRIMARY KEY REFERENCES users ON DELETE CASCADE,
+    encrypted_secret VARCHAR NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_step BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE recovery_codes (
+    code_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE,
+    code_hash VARCHAR NOT NULL,
+    used_at TIMESTAMPTZ,
+    UNIQUE (user_id, code_hash)
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE api_keys (
+    api_key_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    prefix VARCHAR NOT NULL,
+    key_hash VARCHAR NOT NULL UNIQUE,
+    scope VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    user_id BIGINT REFERENCES users ON DELETE CASCADE
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
\ No newline at end of file
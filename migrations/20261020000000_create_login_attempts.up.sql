@@ -0,0 +1,11 @@
+CREATE TABLE login_attempts (
+    attempt_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR NOT NULL,
+    ip VARCHAR NOT NULL,
+    user_id BIGINT REFERENCES users ON DELETE SET NULL,
+    succeeded BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX login_attempts_email_idx ON login_attempts (email, created_at);
+CREATE INDEX login_attempts_ip_idx ON login_attempts (ip, created_at);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN role VARCHAR NOT NULL DEFAULT 'user';
+
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
\ No newline at end of file
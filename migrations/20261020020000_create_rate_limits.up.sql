@@ -0,0 +1,8 @@
+CREATE TABLE rate_limits (
+    key VARCHAR PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    full_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX rate_limits_full_at_idx ON rate_limits (full_at);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX login_attempts_mfa_idx;
+
+ALTER TABLE login_attempts DROP COLUMN mfa;
\ No newline at end of file
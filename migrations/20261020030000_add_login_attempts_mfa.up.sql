@@ -0,0 +1,3 @@
+ALTER TABLE login_attempts ADD COLUMN mfa BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX login_attempts_mfa_idx ON login_attempts (user_id, created_at) WHERE mfa;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE reminders DROP COLUMN next_attempt_at, DROP COLUMN attempts;
\ No newline at end of file
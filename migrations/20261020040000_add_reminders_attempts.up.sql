@@ -0,0 +1 @@
+ALTER TABLE reminders ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0, ADD COLUMN next_attempt_at TIMESTAMPTZ;
\ No newline at end of file